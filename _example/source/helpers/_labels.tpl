@@ -0,0 +1,3 @@
+{{- define "labels.managedBy" -}}
+app.kubernetes.io/managed-by: argocd
+{{- end -}}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/menu"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/project"
+	"sigs.k8s.io/yaml"
+)
+
+// runGet prints an environment, stage or cluster, one of its properties or one of its addon configurations
+// Usage: ogc get <environment|stage|cluster> <path> [property <key>|addon <name>] [--output yaml|json]
+func runGet(w io.Writer, config *project.ProjectConfig, args []string) error {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	fs.SetOutput(w)
+	output := fs.String("output", "yaml", "output format, one of yaml or json")
+	fs.StringVar(output, "o", "yaml", "shorthand for --output")
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 && len(args) != 4 {
+		return fmt.Errorf("usage: ogc get <environment|stage|cluster> <path> [property <key>|addon <name>] [--output yaml|json]")
+	}
+
+	ref, err := project.ParseEntityRef(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	// secrets are masked like in the menus
+	value, err := config.MaskedEntity(ref)
+	if err != nil {
+		return err
+	}
+	if len(args) == 4 {
+		handler := value.(project.AddonHandler)
+		properties := map[string]any{}
+		switch v := value.(type) {
+		case *project.Environment:
+			properties = v.Properties
+		case *project.Stage:
+			properties = v.Properties
+		case *project.Cluster:
+			properties = v.Properties
+		}
+
+		switch args[2] {
+		case "property":
+			v, ok := properties[args[3]]
+			if !ok {
+				return fmt.Errorf("property %s is not set on %s %s", args[3], ref.Kind, ref)
+			}
+			value = v
+		case "addon":
+			ca := handler.GetAddon(args[3])
+			if ca == nil {
+				return fmt.Errorf("addon %s is not configured on %s %s", args[3], ref.Kind, ref)
+			}
+			value = ca
+		default:
+			return fmt.Errorf("unknown field %q, must be property or addon", args[2])
+		}
+	}
+
+	switch *output {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	case "yaml":
+		bts, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bts)
+		return err
+	default:
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+}
+
+// runSet changes properties or addon configurations of an environment, stage or cluster
+// The changes are validated like in the menus and the update events are emitted, so hooks and rendering are executed
+// Usage: ogc set <environment|stage|cluster> <path> property <key>=<value>...
+// Usage: ogc set <environment|stage|cluster> <path> addon <name>.enabled=<bool>|<name>.properties.<key>=<value>...
+func runSet(config *project.ProjectConfig, args []string, emit func(menu.Event) error) error {
+	usage := fmt.Errorf("usage: ogc set <environment|stage|cluster> <path> property <key>=<value>... | addon <name>.enabled=<bool>|<name>.properties.<key>=<value>...")
+	if len(args) < 4 {
+		return usage
+	}
+	ref, err := project.ParseEntityRef(args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	// the assignments are parsed before the pre update hooks run, so invalid arguments do not trigger them
+	changes := []func() error{}
+	for _, assignment := range args[3:] {
+		key, value, ok := strings.Cut(assignment, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid assignment %q, expected <key>=<value>", assignment)
+		}
+		switch args[2] {
+		case "property":
+			changes = append(changes, func() error {
+				return config.SetEntityProperty(ref, key, value)
+			})
+		case "addon":
+			addon, field, ok := strings.Cut(key, ".")
+			if !ok {
+				return fmt.Errorf("invalid addon field %q, expected <name>.enabled or <name>.properties.<key>", key)
+			}
+			changes = append(changes, func() error {
+				return config.SetEntityAddon(ref, addon, field, value)
+			})
+		default:
+			return usage
+		}
+	}
+	return applyChanges(config, ref, changes, emit)
+}
+
+// runUnset removes properties or addon configurations of an environment, stage or cluster, so the values are inherited again
+// Usage: ogc unset <environment|stage|cluster> <path> property <key>...
+// Usage: ogc unset <environment|stage|cluster> <path> addon <name>|<name>.enabled|<name>.properties.<key>...
+func runUnset(config *project.ProjectConfig, args []string, emit func(menu.Event) error) error {
+	usage := fmt.Errorf("usage: ogc unset <environment|stage|cluster> <path> property <key>... | addon <name>|<name>.enabled|<name>.properties.<key>...")
+	if len(args) < 4 {
+		return usage
+	}
+	ref, err := project.ParseEntityRef(args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	changes := []func() error{}
+	for _, key := range args[3:] {
+		switch args[2] {
+		case "property":
+			changes = append(changes, func() error {
+				return config.UnsetEntityProperty(ref, key)
+			})
+		case "addon":
+			addon, field, _ := strings.Cut(key, ".")
+			changes = append(changes, func() error {
+				return config.UnsetEntityAddon(ref, addon, field)
+			})
+		default:
+			return usage
+		}
+	}
+	return applyChanges(config, ref, changes, emit)
+}
+
+// applyChanges applies the changes to the entity between its pre and post update events like the menus do
+// A failing pre update hook prevents the changes
+func applyChanges(config *project.ProjectConfig, ref project.EntityRef, changes []func() error, emit func(menu.Event) error) error {
+	_, err := config.Entity(ref)
+	if err != nil {
+		return err
+	}
+	err = emitUpdateEvent(ref, menu.EventRuntimePre, emit)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		err := change()
+		if err != nil {
+			return err
+		}
+	}
+	return emitUpdateEvent(ref, menu.EventRuntimePost, emit)
+}
+
+// emitUpdateEvent emits the update event of the changed entity with the given runtime
+func emitUpdateEvent(ref project.EntityRef, runtime menu.EventRuntime, emit func(menu.Event) error) error {
+	origin := menu.EventOriginEnvironment
+	switch ref.Kind {
+	case project.EntityKindStage:
+		origin = menu.EventOriginStage
+	case project.EntityKindCluster:
+		origin = menu.EventOriginCluster
+	}
+	return emit(menu.Event{
+		Type:        menu.EventTypeUpdate,
+		Origin:      origin,
+		Runtime:     runtime,
+		Environment: ref.Environment,
+		Stage:       ref.Stage,
+		Cluster:     ref.Cluster,
+	})
+}
+
+// parseInterspersed parses the flags of the flag set, which may be placed between the positional arguments
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+	for {
+		err := fs.Parse(args)
+		if err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/project"
+)
+
+// runExplain prints the effective properties of a cluster or one of its addons together with their origin
+// The cluster is addressed by its path like in the get command, the flags --env, --stage and --cluster are supported as well
+// Usage: ogc explain <env>/<stage>/<cluster> [addon] [--output text|json]
+func runExplain(w io.Writer, config *project.ProjectConfig, args []string) error {
+	const usage = "usage: ogc explain <env>/<stage>/<cluster> [addon] [--output text|json]"
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	fs.SetOutput(w)
+	env := fs.String("env", "", "environment of the cluster")
+	stage := fs.String("stage", "", "stage of the cluster")
+	cluster := fs.String("cluster", "", "name of the cluster")
+	output := fs.String("output", "text", "output format, one of text or json")
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+
+	ref := project.EntityRef{Kind: project.EntityKindCluster, Environment: *env, Stage: *stage, Cluster: *cluster}
+	if *env == "" && *stage == "" && *cluster == "" {
+		if len(args) == 0 {
+			return fmt.Errorf(usage)
+		}
+		ref, err = project.ParseEntityRef(string(project.EntityKindCluster), args[0])
+		if err != nil {
+			return err
+		}
+		args = args[1:]
+	}
+	if len(args) > 1 {
+		return fmt.Errorf(usage)
+	}
+
+	if !config.HasEnvironment(ref.Environment) {
+		return fmt.Errorf("environment %q does not exist", ref.Environment)
+	}
+	if !config.GetEnvironment(ref.Environment).HasStage(ref.Stage) {
+		return fmt.Errorf("stage %q does not exist in environment %s", ref.Stage, ref.Environment)
+	}
+	if !config.HasCluster(ref.Environment, ref.Stage, ref.Cluster) {
+		return fmt.Errorf("cluster %q does not exist in %s/%s", ref.Cluster, ref.Environment, ref.Stage)
+	}
+	c := config.GetCluster(ref.Environment, ref.Stage, ref.Cluster)
+
+	explained := c.ExplainProperties(config, ref.Environment, ref.Stage)
+	if len(args) == 1 {
+		addon := args[0]
+		if _, ok := config.ParsedAddons[addon]; !ok {
+			return fmt.Errorf("addon %q does not exist", addon)
+		}
+		explained = c.ExplainAddonProperties(config, addon, ref.Environment, ref.Stage)
+	}
+
+	switch *output {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(explained)
+	case "text":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "PROPERTY\tVALUE\tORIGIN\tSHADOWED")
+		for _, ep := range explained {
+			shadowed := []string{}
+			for _, sv := range ep.Shadowed {
+				shadowed = append(shadowed, fmt.Sprintf("%s (%s)", formatValue(sv.Value), sv.Origin))
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", ep.Key, formatValue(ep.Value), ep.Origin, strings.Join(shadowed, ", "))
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+}
+
+// formatValue formats a property value for the command output, lists and maps are formatted as json
+func formatValue(value any) string {
+	switch value.(type) {
+	case []any, map[string]any:
+		bts, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprintf("%v", value)
+		}
+		return string(bts)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
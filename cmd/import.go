@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/project"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
+)
+
+// runImport adds the environments, stages and clusters of an existing overlay tree to the project
+// The overlays are not rendered, so they can be compared with the rendered output afterwards
+// Usage: ogc import --from <dir> [--dry-run] [--output text|json]
+func runImport(w io.Writer, config *project.ProjectConfig, args []string, save func() error) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.SetOutput(w)
+	from := fs.String("from", "", "overlay directory laid out as <env>/<stage>/<cluster>/<group>/<addon>")
+	dryRun := fs.Bool("dry-run", false, "only report the changes, the project file is not updated")
+	output := fs.String("output", "text", "output format, one of text or json")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *from == "" || fs.NArg() > 0 {
+		return fmt.Errorf("usage: ogc import --from <dir> [--dry-run] [--output text|json]")
+	}
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+
+	result, err := config.ImportOverlays(*from)
+	if err != nil {
+		return err
+	}
+
+	if *output == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err := enc.Encode(result)
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, env := range result.Environments {
+			fmt.Fprintf(w, "%s environment %s\n", utils.Green.Wrap("created"), env)
+		}
+		for _, stage := range result.Stages {
+			fmt.Fprintf(w, "%s stage %s\n", utils.Green.Wrap("created"), stage)
+		}
+		for _, c := range result.Clusters {
+			action := utils.Green.Wrap("created")
+			if !c.Created {
+				action = utils.Yellow.Wrap("updated")
+			}
+			fmt.Fprintf(w, "%s cluster %s/%s/%s with addons %s\n", action, c.Environment, c.Stage, c.Name, strings.Join(c.Addons, ", "))
+		}
+		for _, u := range result.Unmapped {
+			fmt.Fprintf(w, "%s %s: %s\n", utils.Red.Wrap("unmapped"), u.Path, u.Reason)
+		}
+		for _, warning := range result.Warnings {
+			fmt.Fprintf(w, "%s %s\n", utils.Yellow.Wrap("warning"), warning)
+		}
+	}
+
+	if *dryRun || len(result.Clusters) == 0 {
+		return nil
+	}
+	// the project file must still be loadable after the import
+	err = config.ValidateProperties()
+	if err != nil {
+		return fmt.Errorf("the imported project is invalid and has not been saved: %w", err)
+	}
+	return save()
+}
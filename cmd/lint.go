@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/lint"
+)
+
+// runLint strictly checks the project file and all addon and template manifests
+// Usage: ogc lint [--output text|json]
+func runLint(w io.Writer, projectFile string, args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(w)
+	output := fs.String("output", "text", "output format, one of text or json")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	findings, err := lint.Project(projectFile)
+	if err != nil {
+		return err
+	}
+
+	switch *output {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err := enc.Encode(findings)
+		if err != nil {
+			return err
+		}
+	case "text":
+		for _, f := range findings {
+			fmt.Fprintln(w, f)
+		}
+	default:
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+
+	// warnings are reported but do not fail the command
+	if lint.HasErrors(findings) {
+		errors := 0
+		for _, f := range findings {
+			if f.Severity == lint.SeverityError {
+				errors++
+			}
+		}
+		return fmt.Errorf("lint found %d problem(s)", errors)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/project"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
+	"sigs.k8s.io/yaml"
+)
+
+// labelFlags collects the repeatable --label key[=value] flags
+type labelFlags map[string]string
+
+func (l labelFlags) String() string {
+	return formatLabels(l)
+}
+
+func (l labelFlags) Set(value string) error {
+	key, val, _ := strings.Cut(value, "=")
+	if key == "" {
+		return fmt.Errorf("label key cannot be empty")
+	}
+	l[key] = val
+	return nil
+}
+
+// runList prints the environments, stages, clusters or addons of the project
+// Usage: ogc list <environments|stages|clusters|addons> [--env <env>] [--stage <stage>] [--addon-enabled <addon>] [--label <key>[=<value>]]... [--output table|json|yaml|csv]
+func runList(w io.Writer, config *project.ProjectConfig, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	fs.SetOutput(w)
+	filter := project.InventoryFilter{Labels: labelFlags{}}
+	fs.StringVar(&filter.Environment, "env", "", "only list entities of the environment")
+	fs.StringVar(&filter.Stage, "stage", "", "only list entities of the stage")
+	fs.StringVar(&filter.AddonEnabled, "addon-enabled", "", "only list clusters the addon is enabled for")
+	fs.Var(labelFlags(filter.Labels), "label", "only list clusters with the label, may be repeated")
+	output := fs.String("output", "table", "output format, one of table, json, yaml or csv")
+	fs.StringVar(output, "o", "table", "shorthand for --output")
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("usage: ogc list <environments|stages|clusters|addons> [--env <env>] [--stage <stage>] [--addon-enabled <addon>] [--label <key>[=<value>]]... [--output table|json|yaml|csv]")
+	}
+
+	var (
+		value  any
+		header []string
+		rows   [][]string
+	)
+	switch args[0] {
+	case "environments", "environment", "envs", "env":
+		environments := config.ListEnvironments(filter)
+		value, header = environments, []string{"NAME", "STAGES", "CLUSTERS"}
+		for _, e := range environments {
+			rows = append(rows, []string{e.Name, strconv.Itoa(e.Stages), strconv.Itoa(e.Clusters)})
+		}
+	case "stages", "stage":
+		stages := config.ListStages(filter)
+		value, header = stages, []string{"ENVIRONMENT", "NAME", "CLUSTERS"}
+		for _, s := range stages {
+			rows = append(rows, []string{s.Environment, s.Name, strconv.Itoa(s.Clusters)})
+		}
+	case "clusters", "cluster":
+		clusters := config.ListClusters(filter)
+		value, header = clusters, []string{"ENVIRONMENT", "STAGE", "NAME", "LABELS", "ADDONS"}
+		for _, c := range clusters {
+			rows = append(rows, []string{c.Environment, c.Stage, c.Name, formatLabels(c.Labels), strings.Join(c.Addons, ",")})
+		}
+	case "addons", "addon":
+		addons := config.ListAddons(filter)
+		value, header = addons, []string{"NAME", "GROUP", "DEFAULT ENABLED", "CLUSTERS", "PATH"}
+		for _, a := range addons {
+			rows = append(rows, []string{a.Name, a.Group, strconv.FormatBool(a.DefaultEnabled), strconv.Itoa(a.Clusters), a.Path})
+		}
+	default:
+		return fmt.Errorf("unknown kind %q, must be one of environments, stages, clusters or addons", args[0])
+	}
+
+	switch *output {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	case "yaml":
+		bts, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bts)
+		return err
+	case "csv":
+		cw := csv.NewWriter(w)
+		for i := range header {
+			header[i] = strings.ToLower(strings.ReplaceAll(header[i], " ", "_"))
+		}
+		err := cw.WriteAll(append([][]string{header}, rows...))
+		if err != nil {
+			return err
+		}
+		return cw.Error()
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+}
+
+// formatLabels formats the labels as comma separated key=value pairs sorted by key
+func formatLabels(labels map[string]string) string {
+	pairs := []string{}
+	for _, key := range utils.SortStringSlice(utils.MapKeysToList(labels)) {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+	return strings.Join(pairs, ",")
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/menu"
 	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/project"
 	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
 )
 
 var (
@@ -20,8 +21,8 @@ const (
 	PROJECTFILENAME = "PROJECT.yaml"
 )
 
-// check for project file and load it
-func init() {
+// loadProjectConfig checks for the project file and loads it
+func loadProjectConfig() {
 	_, err := os.Stat(PROJECTFILENAME)
 	if err != nil && !errors.Is(err, os.ErrNotExist) {
 		fmt.Println("An error occurred while checking for the PROJECT.yaml file", err)
@@ -54,6 +55,26 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		// the project file is linted before it is loaded, so broken files can be reported
+		err := runLint(os.Stdout, PROJECTFILENAME, os.Args[2:])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	loadProjectConfig()
+	if len(os.Args) > 1 {
+		err := runCommand(os.Stdout, os.Args[1], os.Args[2:])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	eventsPipeline := make(chan menu.Event, 100)
 	ctx, cf := context.WithCancel(context.Background())
 	defer cf()
@@ -66,57 +87,16 @@ func main() {
 				close(eventsPipeline)
 				return
 			case event := <-eventsPipeline:
-				// we only need to update the config file if the action is a post action
-				// because we need to update the config only, if the action was successful
-				if event.Runtime == menu.EventRuntimePost {
-					// update config file
-					err := project.UpdateOrCreateConfig(PROJECTFILENAME, projectConfig)
-					if err != nil {
-						fmt.Println("An error occurred while updating the project config", err)
-						return
-					}
-				}
-
-				if event.Origin == menu.EventOriginAddon {
-					if event.Runtime == menu.EventRuntimePre {
-						addonPath := projectConfig.Addons[event.Environment].Path
-						_, err := template.LoadManifest(projectConfig.Addons[event.Environment].Path)
-						if err != nil {
-							fmt.Printf("An error occurred while loading the addon [%s] manifest file: %s, %v\n", event.Environment, addonPath, err)
-							os.Exit(1)
-							return
-						}
-					}
+				err := handleEvent(event)
+				var violations project.PolicyViolations
+				if errors.As(err, &violations) {
+					// the violations are reported, later changes must still be saved and rendered
+					fmt.Println(utils.Red.Wrap("The change has not been rendered:"), err)
 					continue
 				}
-
-				if event.Environment != "" && event.Stage == "" && event.Cluster == "" {
-					env := projectConfig.GetEnvironment(event.Environment)
-					err := executeHook(os.Stdout, os.Stderr, event.Type, event.Runtime, env.Actions)
-					if err != nil {
-						fmt.Println(err)
-						return
-					}
-				}
-
-				if event.Environment != "" && event.Stage != "" && event.Cluster == "" {
-					stage := projectConfig.GetStage(event.Environment, event.Stage)
-					err := executeHook(os.Stdout, os.Stderr, event.Type, event.Runtime, stage.Actions)
-					if err != nil {
-						fmt.Println(err)
-						return
-					}
-				}
-
-				if event.Environment != "" && event.Stage != "" && event.Cluster != "" {
-					cluster := projectConfig.GetCluster(event.Environment, event.Stage, event.Cluster)
-					if event.Type == menu.EventTypeCreate || event.Type == menu.EventTypeUpdate {
-						err := cluster.Render(projectConfig, event.Environment, event.Stage)
-						if err != nil {
-							fmt.Printf("An error occurred while rendering the cluster [%s] configuration: %v", event.Cluster, err)
-							return
-						}
-					}
+				if err != nil {
+					fmt.Println(err)
+					return
 				}
 			}
 		}
@@ -129,6 +109,156 @@ func main() {
 	}
 }
 
+// handleEvent persists the project config after successful changes, executes the hooks and renders the affected templates
+func handleEvent(event menu.Event) error {
+	// we only need to update the config file if the action is a post action
+	// because we need to update the config only, if the action was successful
+	if event.Runtime == menu.EventRuntimePost {
+		// update config file
+		err := project.UpdateOrCreateConfig(PROJECTFILENAME, projectConfig)
+		if err != nil {
+			return fmt.Errorf("an error occurred while updating the project config: %w", err)
+		}
+	}
+
+	if event.Origin == menu.EventOriginAddon {
+		if event.Runtime == menu.EventRuntimePre {
+			addonPath := projectConfig.Addons[event.Environment].Path
+			_, err := template.LoadManifest(projectConfig.Addons[event.Environment].Path)
+			if err != nil {
+				fmt.Printf("An error occurred while loading the addon [%s] manifest file: %s, %v\n", event.Environment, addonPath, err)
+				os.Exit(1)
+			}
+		}
+		return nil
+	}
+
+	if event.Environment != "" && event.Stage == "" && event.Cluster == "" {
+		env := projectConfig.GetEnvironment(event.Environment)
+		err := executeHook(os.Stdout, os.Stderr, event.Type, event.Runtime, env.Actions)
+		if err != nil {
+			return err
+		}
+	}
+
+	if event.Environment != "" && event.Stage != "" && event.Cluster == "" {
+		stage := projectConfig.GetStage(event.Environment, event.Stage)
+		err := executeHook(os.Stdout, os.Stderr, event.Type, event.Runtime, stage.Actions)
+		if err != nil {
+			return err
+		}
+	}
+
+	// environment and stage templates have access to all child clusters,
+	// so we need to render them whenever one of its children changes
+	if event.Runtime == menu.EventRuntimePost && event.Environment != "" {
+		err := renderScopedTemplates(event.Environment, event.Stage)
+		if err != nil {
+			return err
+		}
+	}
+
+	if event.Environment != "" && event.Stage != "" && event.Cluster != "" {
+		cluster := projectConfig.GetCluster(event.Environment, event.Stage, event.Cluster)
+		if event.Type == menu.EventTypeCreate || event.Type == menu.EventTypeUpdate {
+			files, err := cluster.Render(projectConfig, event.Environment, event.Stage)
+			if err != nil {
+				return fmt.Errorf("an error occurred while rendering the cluster [%s] configuration: %w", event.Cluster, err)
+			}
+			printOverrides(os.Stdout, files)
+			printValidationErrors(os.Stdout, files)
+			verifyKustomizeBuild(os.Stdout, cluster, event.Environment, event.Stage)
+		}
+	}
+	return nil
+}
+
+// runCommand executes the non-interactive command with the given arguments
+func runCommand(w io.Writer, command string, args []string) error {
+	switch command {
+	case "explain":
+		return runExplain(w, projectConfig, args)
+	case "policy":
+		return runPolicy(w, projectConfig, args)
+	case "list":
+		return runList(w, projectConfig, args)
+	case "report":
+		return runReport(w, projectConfig, args)
+	case "import":
+		return runImport(w, projectConfig, args, func() error {
+			return project.UpdateOrCreateConfig(PROJECTFILENAME, projectConfig)
+		})
+	case "get":
+		return runGet(w, projectConfig, args)
+	case "set":
+		return runSet(projectConfig, args, handleEvent)
+	case "unset":
+		return runUnset(projectConfig, args, handleEvent)
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+// renderScopedTemplates renders the stage (if given) and environment scoped templates
+// Environments and stages that no longer exist are skipped
+func renderScopedTemplates(env, stage string) error {
+	if !projectConfig.HasEnvironment(env) {
+		return nil
+	}
+	if stage != "" && projectConfig.GetEnvironment(env).HasStage(stage) {
+		files, err := projectConfig.GetStage(env, stage).Render(projectConfig, env)
+		if err != nil {
+			return fmt.Errorf("an error occurred while rendering the stage [%s] templates: %w", stage, err)
+		}
+		printOverrides(os.Stdout, files)
+		printValidationErrors(os.Stdout, files)
+	}
+	files, err := projectConfig.GetEnvironment(env).Render(projectConfig)
+	if err != nil {
+		return fmt.Errorf("an error occurred while rendering the environment [%s] templates: %w", env, err)
+	}
+	printOverrides(os.Stdout, files)
+	printValidationErrors(os.Stdout, files)
+	return nil
+}
+
+// printOverrides reports all rendered files that have been created from an environment or stage specific override
+func printOverrides(w io.Writer, files []template.RenderedFile) {
+	for _, file := range files {
+		if !file.Override {
+			continue
+		}
+		fmt.Fprintf(w, "%s %s (from %s)\n", utils.Yellow.Wrap("override"), file.Path, file.Source)
+	}
+}
+
+// printValidationErrors reports all problems of the rendered files, e.g. broken yaml or resources that do not match their schema
+func printValidationErrors(w io.Writer, files []template.RenderedFile) {
+	err := projectConfig.ValidateRenderedFiles(files)
+	if err != nil {
+		fmt.Fprintln(w, utils.Red.Wrap("The rendered files are invalid:"))
+		fmt.Fprintln(w, err)
+	}
+}
+
+// verifyKustomizeBuild runs kustomize build on the rendered directories of the cluster, if enabled, and reports the results
+func verifyKustomizeBuild(w io.Writer, cluster *project.Cluster, env, stage string) {
+	results, err := cluster.VerifyKustomizeBuild(projectConfig, env, stage)
+	if err != nil {
+		fmt.Fprintln(w, utils.Red.Wrap("An error occurred while verifying the rendered cluster:"), err)
+		return
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Fprintf(w, "%s %s: %v\n", utils.Red.Wrap("kustomize build failed"), result.Path, result.Err)
+			continue
+		}
+		if result.OutputPath != "" {
+			fmt.Fprintf(w, "%s %s (hydrated to %s)\n", utils.Green.Wrap("kustomize build"), result.Path, result.OutputPath)
+		}
+	}
+}
+
 func executeHook(stdout, errout io.Writer, t menu.EventType, r menu.EventRuntime, actions project.Actions) error {
 	switch t {
 	case menu.EventTypeCreate:
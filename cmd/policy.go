@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/project"
+)
+
+// runPolicy evaluates the policies of the project against all clusters
+// Usage: ogc policy check [--output text|json]
+func runPolicy(w io.Writer, config *project.ProjectConfig, args []string) error {
+	if len(args) == 0 || args[0] != "check" {
+		return fmt.Errorf("usage: ogc policy check [--output text|json]")
+	}
+	fs := flag.NewFlagSet("policy check", flag.ContinueOnError)
+	fs.SetOutput(w)
+	output := fs.String("output", "text", "output format, one of text or json")
+	err := fs.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+
+	violations, err := config.CheckPolicies()
+	if err != nil {
+		return err
+	}
+
+	switch *output {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err := enc.Encode(violations)
+		if err != nil {
+			return err
+		}
+	case "text":
+		for _, v := range violations {
+			fmt.Fprintln(w, v)
+		}
+	default:
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("found %d policy violation(s)", len(violations))
+	}
+	return nil
+}
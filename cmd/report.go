@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/project"
+)
+
+// runReport prints the addon adoption matrix with a row per cluster and a column per addon
+// Usage: ogc report addons [--env <env>] [--stage <stage>] [--label <key>[=<value>]]... [--output markdown|csv|html]
+func runReport(w io.Writer, config *project.ProjectConfig, args []string) error {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	fs.SetOutput(w)
+	filter := project.InventoryFilter{Labels: labelFlags{}}
+	fs.StringVar(&filter.Environment, "env", "", "only report the clusters of the environment")
+	fs.StringVar(&filter.Stage, "stage", "", "only report the clusters of the stage")
+	fs.Var(labelFlags(filter.Labels), "label", "only report the clusters with the label, may be repeated")
+	output := fs.String("output", "markdown", "output format, one of markdown, csv or html")
+	fs.StringVar(output, "o", "markdown", "shorthand for --output")
+	args, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 || args[0] != "addons" {
+		return fmt.Errorf("usage: ogc report addons [--env <env>] [--stage <stage>] [--label <key>[=<value>]]... [--output markdown|csv|html]")
+	}
+
+	matrix := config.AddonAdoption(filter)
+	switch *output {
+	case "markdown", "md":
+		return writeAdoptionMarkdown(w, matrix)
+	case "csv":
+		return writeAdoptionCSV(w, matrix)
+	case "html":
+		return writeAdoptionHTML(w, matrix)
+	default:
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+}
+
+// adoptionState returns the enablement of the cell together with the level that decided it, e.g. enabled (stage)
+func adoptionState(cell project.AdoptionCell) string {
+	state := "disabled"
+	if cell.Enabled {
+		state = "enabled"
+	}
+	return fmt.Sprintf("%s (%s)", state, cell.Origin)
+}
+
+// adoptionProperties returns the non-default properties of the cell as key=value pairs
+func adoptionProperties(cell project.AdoptionCell) []string {
+	properties := []string{}
+	for _, ep := range cell.Properties {
+		properties = append(properties, fmt.Sprintf("%s=%s", ep.Key, formatValue(ep.Value)))
+	}
+	return properties
+}
+
+// adoptionSummary returns the number of clusters each addon is enabled for, in the order of the addons
+func adoptionSummary(matrix project.AdoptionMatrix) []string {
+	summary := []string{}
+	for idx := range matrix.Addons {
+		enabled := 0
+		for _, row := range matrix.Rows {
+			if row.Cells[idx].Enabled {
+				enabled++
+			}
+		}
+		summary = append(summary, fmt.Sprintf("%d/%d", enabled, len(matrix.Rows)))
+	}
+	return summary
+}
+
+func writeAdoptionMarkdown(w io.Writer, matrix project.AdoptionMatrix) error {
+	escape := strings.NewReplacer("|", `\|`, "\n", " ").Replace
+	header := append([]string{"Environment", "Stage", "Cluster"}, matrix.Addons...)
+	fmt.Fprintf(w, "| %s |\n", strings.Join(header, " | "))
+	fmt.Fprintf(w, "|%s\n", strings.Repeat(" --- |", len(header)))
+	for _, row := range matrix.Rows {
+		cells := []string{row.Environment, row.Stage, row.Cluster}
+		for _, cell := range row.Cells {
+			text := adoptionState(cell)
+			for _, property := range adoptionProperties(cell) {
+				text += "<br>`" + strings.ReplaceAll(property, "`", "'") + "`"
+			}
+			cells = append(cells, escape(text))
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+	}
+	_, err := fmt.Fprintf(w, "| **Enabled** | | | %s |\n", strings.Join(adoptionSummary(matrix), " | "))
+	return err
+}
+
+func writeAdoptionCSV(w io.Writer, matrix project.AdoptionMatrix) error {
+	cw := csv.NewWriter(w)
+	// every addon has a state and a properties column, so spreadsheets can filter by state
+	header := []string{"environment", "stage", "cluster"}
+	for _, addon := range matrix.Addons {
+		header = append(header, addon, addon+"_properties")
+	}
+	err := cw.Write(header)
+	if err != nil {
+		return err
+	}
+	for _, row := range matrix.Rows {
+		record := []string{row.Environment, row.Stage, row.Cluster}
+		for _, cell := range row.Cells {
+			record = append(record, adoptionState(cell), strings.Join(adoptionProperties(cell), "; "))
+		}
+		err := cw.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+var adoptionHTML = template.Must(template.New("adoption").Funcs(template.FuncMap{
+	"state":      adoptionState,
+	"properties": adoptionProperties,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Addon adoption</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.4em 0.6em; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; position: sticky; top: 0; }
+td.enabled { background: #e6f4ea; }
+td.disabled { background: #fce8e6; }
+code { display: block; font-size: 0.85em; }
+</style>
+</head>
+<body>
+<h1>Addon adoption</h1>
+<p>Generated at {{ .Generated }}</p>
+<table>
+<thead>
+<tr><th>Environment</th><th>Stage</th><th>Cluster</th>{{ range .Matrix.Addons }}<th>{{ . }}</th>{{ end }}</tr>
+</thead>
+<tbody>
+{{- range .Matrix.Rows }}
+<tr><td>{{ .Environment }}</td><td>{{ .Stage }}</td><td>{{ .Cluster }}</td>
+{{- range .Cells }}<td class="{{ if .Enabled }}enabled{{ else }}disabled{{ end }}">{{ state . }}{{ range properties . }}<code>{{ . }}</code>{{ end }}</td>{{ end }}</tr>
+{{- end }}
+</tbody>
+<tfoot>
+<tr><th colspan="3">Enabled</th>{{ range .Summary }}<th>{{ . }}</th>{{ end }}</tr>
+</tfoot>
+</table>
+</body>
+</html>
+`))
+
+func writeAdoptionHTML(w io.Writer, matrix project.AdoptionMatrix) error {
+	return adoptionHTML.Execute(w, map[string]any{
+		"Matrix":    matrix,
+		"Summary":   adoptionSummary(matrix),
+		"Generated": time.Now().UTC().Format(time.RFC3339),
+	})
+}
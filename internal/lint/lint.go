@@ -0,0 +1,571 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/project"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/secret"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
+	"sigs.k8s.io/yaml"
+	yamlv3 "sigs.k8s.io/yaml/goyaml.v3"
+)
+
+// Rule identifies the check that reported a finding
+type Rule string
+
+const (
+	RuleInvalidYAML       Rule = "invalid-yaml"
+	RuleUnknownField      Rule = "unknown-field"
+	RuleDuplicateKey      Rule = "duplicate-key"
+	RuleDuplicateAddon    Rule = "duplicate-addon"
+	RuleDuplicateTemplate Rule = "duplicate-template"
+	RuleInvalidType       Rule = "invalid-type"
+	RuleInvalidDefault    Rule = "invalid-default"
+	RuleInvalidValue      Rule = "invalid-value"
+	RuleMissingFile       Rule = "missing-file"
+	RuleInvalidTemplate   Rule = "invalid-template"
+	RuleInvalidName       Rule = "invalid-name"
+
+	RuleUndeclaredProperty Rule = "undeclared-property"
+	RuleUndeclaredAddon    Rule = "undeclared-addon"
+	RuleUnusedProperty     Rule = "unused-property"
+	RulePlaintextSecret    Rule = "plaintext-secret"
+)
+
+// Severity defines if a finding is an error or only a warning
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// severity returns the severity of the findings of the rule
+func (r Rule) severity() Severity {
+	switch r {
+	case RuleUndeclaredProperty, RuleUndeclaredAddon, RuleUnusedProperty, RulePlaintextSecret:
+		return SeverityWarning
+	}
+	return SeverityError
+}
+
+// Finding is a problem found in one of the project files
+type Finding struct {
+	File string `json:"file"`
+	// Line and Column point to the position of the problem in the file, they are 0 if unknown
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+	// Path is the field path of the problem, e.g. properties.gitURL.type
+	Path     string   `json:"path,omitempty"`
+	Rule     Rule     `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// String formats the finding as file:line:column: severity: message (rule)
+func (f Finding) String() string {
+	position := f.File
+	if f.Line > 0 {
+		position = fmt.Sprintf("%s:%d:%d", f.File, f.Line, f.Column)
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", position, f.Severity, f.Message, f.Rule)
+}
+
+// HasErrors checks if one of the findings is an error
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// linter collects the findings of all linted files
+type linter struct {
+	findings []Finding
+}
+
+// Project lints the project file and all addon and template manifests referenced by it
+// The findings are sorted by file and position
+func Project(path string) ([]Finding, error) {
+	l := &linter{findings: []Finding{}}
+	config := &project.ProjectConfig{}
+	root, ok, err := l.decode(path, config)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		l.lintProjectConfig(path, root, config)
+	}
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		if l.findings[i].File != l.findings[j].File {
+			return l.findings[i].File < l.findings[j].File
+		}
+		if l.findings[i].Line != l.findings[j].Line {
+			return l.findings[i].Line < l.findings[j].Line
+		}
+		return l.findings[i].Column < l.findings[j].Column
+	})
+	return l.findings, nil
+}
+
+// add adds a finding for the given node, the node may be nil
+func (l *linter) add(file string, node *yamlv3.Node, path string, rule Rule, format string, args ...any) {
+	f := Finding{
+		File:     file,
+		Path:     path,
+		Rule:     rule,
+		Severity: rule.severity(),
+		Message:  fmt.Sprintf(format, args...),
+	}
+	if node != nil {
+		f.Line = node.Line
+		f.Column = node.Column
+	}
+	l.findings = append(l.findings, f)
+}
+
+// decode parses the yaml file, checks all fields against the given type and decodes it into the target
+// false is returned if the file could not be decoded, the reason has been added to the findings
+func (l *linter) decode(path string, target any) (*yamlv3.Node, bool, error) {
+	bts, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+	root := &yamlv3.Node{}
+	err = yamlv3.Unmarshal(bts, root)
+	if err != nil {
+		l.add(path, nil, "", RuleInvalidYAML, "%v", err)
+		return nil, false, nil
+	}
+	l.checkFields(path, root, reflect.TypeOf(target), "")
+
+	err = yaml.Unmarshal(bts, target)
+	if err != nil {
+		l.add(path, nil, "", RuleInvalidYAML, "%v", err)
+		return root, false, nil
+	}
+	return root, true, nil
+}
+
+// checkFields reports unknown fields and duplicate keys of the node
+// Fields are compared with the json names of the given type, values of type any are only checked for duplicate keys
+func (l *linter) checkFields(file string, node *yamlv3.Node, typ reflect.Type, path string) {
+	switch node.Kind {
+	case yamlv3.DocumentNode:
+		for _, n := range node.Content {
+			l.checkFields(file, n, typ, path)
+		}
+		return
+	case yamlv3.AliasNode:
+		l.checkFields(file, node.Alias, typ, path)
+		return
+	}
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch node.Kind {
+	case yamlv3.SequenceNode:
+		elem := typ
+		if typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+			elem = typ.Elem()
+		}
+		for idx, n := range node.Content {
+			l.checkFields(file, n, elem, fmt.Sprintf("%s[%d]", path, idx))
+		}
+	case yamlv3.MappingNode:
+		fields := map[string]reflect.Type{}
+		if typ.Kind() == reflect.Struct {
+			fields = jsonFields(typ)
+		}
+		seen := map[string]bool{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			fieldPath := joinPath(path, key.Value)
+			if seen[key.Value] {
+				rule := RuleDuplicateKey
+				if path == "addons" && typ.Kind() == reflect.Map {
+					rule = RuleDuplicateAddon
+				}
+				l.add(file, key, fieldPath, rule, "key %s is defined more than once", key.Value)
+			}
+			seen[key.Value] = true
+
+			switch typ.Kind() {
+			case reflect.Struct:
+				fieldType, ok := fields[key.Value]
+				if !ok {
+					l.add(file, key, fieldPath, RuleUnknownField, "unknown field %s", key.Value)
+					continue
+				}
+				l.checkFields(file, value, fieldType, fieldPath)
+			case reflect.Map:
+				l.checkFields(file, value, typ.Elem(), fieldPath)
+			default:
+				l.checkFields(file, value, typ, fieldPath)
+			}
+		}
+	}
+}
+
+// jsonFields returns the json field names of the struct and their types, fields of embedded structs are inlined
+func jsonFields(typ reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for k, v := range jsonFields(field.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// lintProjectConfig lints the property schema of the project and all referenced addons and templates
+func (l *linter) lintProjectConfig(file string, root *yamlv3.Node, config *project.ProjectConfig) {
+	for _, key := range utils.SortStringSlice(utils.MapKeysToList(config.PropertySchema)) {
+		definition := config.PropertySchema[key]
+		path := joinPath("propertySchema", key)
+		l.lintProperty(file, root, path, definition.Property)
+		switch definition.RequiredAt {
+		case "", project.PropertyLevelEnvironment, project.PropertyLevelStage, project.PropertyLevelCluster:
+		default:
+			l.add(file, lookup(root, joinPath(path, "requiredAt")), joinPath(path, "requiredAt"), RuleInvalidValue, "unknown level %s", definition.RequiredAt)
+		}
+	}
+	if config.SchemaPath != "" {
+		if _, err := os.Stat(config.SchemaPath); err != nil {
+			l.add(file, lookup(root, "schemaPath"), "schemaPath", RuleMissingFile, "%v", err)
+		}
+	}
+
+	addons := map[string]*lintedManifest{}
+	manifestNames := map[string]string{}
+	for _, name := range utils.SortStringSlice(utils.MapKeysToList(config.Addons)) {
+		path := joinPath("addons", name, "path")
+		manifestPath, err := manifestFile(config.Addons[name].Path)
+		if err != nil {
+			l.add(file, lookup(root, path), path, RuleMissingFile, "addon %s: %v", name, err)
+			continue
+		}
+		tm := l.lintManifest(manifestPath)
+		if tm == nil {
+			continue
+		}
+		addons[name] = tm
+		if tm.Name == "" {
+			continue
+		}
+		if other, ok := manifestNames[tm.Name]; ok {
+			l.add(manifestPath, lookup(tm.node, "name"), "name", RuleDuplicateAddon, "addon name %s is already used by addon %s", tm.Name, other)
+			continue
+		}
+		manifestNames[tm.Name] = name
+	}
+
+	templates := l.lintTemplates(file, root, config.TemplateBasePath)
+	l.lintReferences(file, root, config, addons, templates)
+	l.lintNames(file, root, config)
+	l.lintSecrets(file, root, config, addons)
+}
+
+// lintSecrets reports the values of secret properties that are stored in plain text
+// Without a secret key, the values are not encrypted when the project is loaded
+func (l *linter) lintSecrets(file string, root *yamlv3.Node, config *project.ProjectConfig, addons map[string]*lintedManifest) {
+	check := func(path string, schema map[string]template.Property, properties map[string]any) {
+		for _, key := range utils.SortStringSlice(utils.MapKeysToList(properties)) {
+			value, ok := properties[key].(string)
+			if !ok || schema[key].Type != template.PropertyTypeSecret || value == template.DeleteMarker || secret.IsEncrypted(value) {
+				continue
+			}
+			path := joinPath(path, "properties", key)
+			l.add(file, lookup(root, path), path, RulePlaintextSecret, "secret property %s is stored in plain text", key)
+		}
+	}
+	checkLevel := func(path string, properties map[string]any, clusterAddons map[string]*project.ClusterAddon) {
+		check(path, config.PropertyDefinitions(), properties)
+		for _, addonName := range utils.SortStringSlice(utils.MapKeysToList(clusterAddons)) {
+			tm, ok := addons[addonName]
+			if !ok || clusterAddons[addonName] == nil {
+				continue
+			}
+			check(joinPath(path, "addons", addonName), tm.Properties, clusterAddons[addonName].Properties)
+		}
+	}
+
+	for _, envName := range utils.SortStringSlice(utils.MapKeysToList(config.Environments)) {
+		env := config.Environments[envName]
+		checkLevel(joinPath("environments", envName), env.Properties, env.Addons)
+		for _, stageName := range utils.SortStringSlice(utils.MapKeysToList(env.Stages)) {
+			stage := env.Stages[stageName]
+			checkLevel(joinPath("environments", envName, "stages", stageName), stage.Properties, stage.Addons)
+			for _, clusterName := range utils.SortStringSlice(utils.MapKeysToList(stage.Clusters)) {
+				cluster := stage.Clusters[clusterName]
+				checkLevel(joinPath("environments", envName, "stages", stageName, "clusters", clusterName), cluster.Properties, cluster.Addons)
+			}
+		}
+	}
+}
+
+// lintNames checks the names of all environments, stages and clusters against the naming policy of the project
+// Levels with an invalid naming pattern are only reported once
+func (l *linter) lintNames(file string, root *yamlv3.Node, config *project.ProjectConfig) {
+	naming := config.Naming
+	if naming == nil {
+		naming = &project.NamingPolicy{}
+	}
+	validPattern := func(level string, rule *project.NamingRule) bool {
+		if rule == nil || rule.Pattern == "" {
+			return true
+		}
+		_, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			path := joinPath("naming", level, "pattern")
+			l.add(file, lookup(root, path), path, RuleInvalidValue, "invalid naming pattern: %v", err)
+			return false
+		}
+		return true
+	}
+	checkEnvironments := validPattern("environments", naming.Environments)
+	checkStages := validPattern("stages", naming.Stages)
+	checkClusters := validPattern("clusters", naming.Clusters)
+
+	for _, envName := range utils.SortStringSlice(utils.MapKeysToList(config.Environments)) {
+		path := joinPath("environments", envName)
+		if err := config.ValidateEnvironmentName(envName); checkEnvironments && err != nil {
+			l.add(file, lookupKey(root, path), path, RuleInvalidName, "environment %v", err)
+		}
+		env := config.Environments[envName]
+		for _, stageName := range utils.SortStringSlice(utils.MapKeysToList(env.Stages)) {
+			path := joinPath("environments", envName, "stages", stageName)
+			if err := config.ValidateStageName(stageName); checkStages && err != nil {
+				l.add(file, lookupKey(root, path), path, RuleInvalidName, "stage %v", err)
+			}
+			for _, clusterName := range utils.SortStringSlice(utils.MapKeysToList(env.Stages[stageName].Clusters)) {
+				path := joinPath("environments", envName, "stages", stageName, "clusters", clusterName)
+				if err := config.ValidateClusterName(envName, stageName, clusterName); checkClusters && err != nil {
+					l.add(file, lookupKey(root, path), path, RuleInvalidName, "cluster %v", err)
+				}
+			}
+		}
+	}
+}
+
+// lintTemplates lints all template manifests in the template base path and returns the decoded manifests
+func (l *linter) lintTemplates(file string, root *yamlv3.Node, basePath string) []*lintedManifest {
+	templates := []*lintedManifest{}
+	if basePath == "" {
+		return templates
+	}
+	if _, err := os.Stat(basePath); err != nil {
+		l.add(file, lookup(root, "templateBasePath"), "templateBasePath", RuleMissingFile, "%v", err)
+		return templates
+	}
+	templateNames := map[string]string{}
+	_ = filepath.WalkDir(basePath, func(fpath string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || (d.Name() != "manifest.yaml" && d.Name() != "manifest.yml") {
+			return nil
+		}
+		tm := l.lintManifest(fpath)
+		if tm == nil {
+			return nil
+		}
+		templates = append(templates, tm)
+		switch tm.Scope {
+		case "", template.TemplateScopeCluster, template.TemplateScopeStage, template.TemplateScopeEnvironment:
+		default:
+			l.add(fpath, lookup(tm.node, "scope"), "scope", RuleInvalidValue, "unknown scope %s", tm.Scope)
+		}
+		if other, ok := templateNames[tm.Name]; ok {
+			l.add(fpath, lookup(tm.node, "name"), "name", RuleDuplicateTemplate, "template name %s is already used by %s", tm.Name, other)
+			return nil
+		}
+		templateNames[tm.Name] = fpath
+		return nil
+	})
+	return templates
+}
+
+// lintedManifest is a manifest that has been decoded successfully together with its location and yaml node
+type lintedManifest struct {
+	template.TemplateManifest
+	path string
+	node *yamlv3.Node
+}
+
+// lintManifest lints the addon or template manifest, nil is returned if the manifest could not be decoded
+func (l *linter) lintManifest(path string) *lintedManifest {
+	tm := &template.TemplateManifest{}
+	root, ok, err := l.decode(path, tm)
+	if err != nil {
+		l.add(path, nil, "", RuleMissingFile, "%v", err)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+
+	for _, key := range utils.SortStringSlice(utils.MapKeysToList(tm.Properties)) {
+		l.lintProperty(path, root, joinPath("properties", key), tm.Properties[key])
+	}
+	for idx, file := range tm.Files {
+		_, err := os.Stat(filepath.Join(filepath.Dir(path), file))
+		if err != nil {
+			fieldPath := fmt.Sprintf("files[%d]", idx)
+			l.add(path, lookup(root, fieldPath), fieldPath, RuleMissingFile, "file %s does not exist", file)
+		}
+	}
+	return &lintedManifest{TemplateManifest: *tm, path: path, node: root}
+}
+
+// lintProperty checks the type and default of the property definition and its nested definitions
+func (l *linter) lintProperty(file string, root *yamlv3.Node, path string, property template.Property) {
+	if property.Pattern != "" {
+		_, err := regexp.Compile(property.Pattern)
+		if err != nil {
+			patternPath := joinPath(path, "pattern")
+			l.add(file, lookup(root, patternPath), patternPath, RuleInvalidValue, "invalid pattern: %v", err)
+			// the default can not be checked against an invalid pattern
+			property.Default = nil
+		}
+	}
+
+	if !property.Type.IsValid() {
+		typePath := joinPath(path, "type")
+		node := lookup(root, typePath)
+		if node == nil {
+			l.add(file, lookup(root, path), path, RuleInvalidType, "property has no type")
+		} else {
+			l.add(file, node, typePath, RuleInvalidType, "unknown property type %q", property.Type)
+		}
+	} else if property.Default != nil && !property.HasTemplatedDefault() {
+		_, err := property.ParseValue(property.Default)
+		if err != nil {
+			defaultPath := joinPath(path, "default")
+			l.add(file, lookup(root, defaultPath), defaultPath, RuleInvalidDefault, "default does not match the property definition: %v", err)
+		}
+	}
+
+	if property.Items != nil {
+		l.lintProperty(file, root, joinPath(path, "items"), *property.Items)
+	}
+	for _, key := range utils.SortStringSlice(utils.MapKeysToList(property.Properties)) {
+		l.lintProperty(file, root, joinPath(path, "properties", key), property.Properties[key])
+	}
+}
+
+// manifestFile returns the path of the manifest file in the given directory
+func manifestFile(dir string) (string, error) {
+	for _, name := range []string{"manifest.yaml", "manifest.yml"} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no manifest file found in %s", dir)
+}
+
+// lookup returns the value node of the given field path, nil is returned if it does not exist
+func lookup(node *yamlv3.Node, path string) *yamlv3.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yamlv3.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	for _, segment := range splitPath(path) {
+		var next *yamlv3.Node
+		switch node.Kind {
+		case yamlv3.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == segment {
+					next = node.Content[i+1]
+				}
+			}
+		case yamlv3.SequenceNode:
+			var idx int
+			if _, err := fmt.Sscanf(segment, "[%d]", &idx); err == nil && idx < len(node.Content) {
+				next = node.Content[idx]
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+// lookupKey returns the key node of the given field path, nil is returned if it does not exist
+func lookupKey(node *yamlv3.Node, path string) *yamlv3.Node {
+	segments := splitPath(path)
+	if len(segments) == 0 {
+		return nil
+	}
+	parent := lookup(node, joinPath(segments[:len(segments)-1]...))
+	if parent == nil || parent.Kind != yamlv3.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == segments[len(segments)-1] {
+			return parent.Content[i]
+		}
+	}
+	return nil
+}
+
+// joinPath joins the segments of a field path with dots
+func joinPath(segments ...string) string {
+	result := ""
+	for _, segment := range segments {
+		switch {
+		case result == "":
+			result = segment
+		case strings.HasPrefix(segment, "["):
+			result += segment
+		default:
+			result += "." + segment
+		}
+	}
+	return result
+}
+
+// splitPath splits the field path into its segments, list indices are separate segments
+func splitPath(path string) []string {
+	segments := []string{}
+	for _, segment := range strings.Split(path, ".") {
+		name, index, found := strings.Cut(segment, "[")
+		if name != "" {
+			segments = append(segments, name)
+		}
+		if found {
+			for _, idx := range strings.Split(index, "[") {
+				segments = append(segments, "["+idx)
+			}
+		}
+	}
+	return segments
+}
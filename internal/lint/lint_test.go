@@ -0,0 +1,317 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProject(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []Finding
+	}{
+		{
+			name: "valid project",
+			files: map[string]string{
+				"PROJECT.yaml": `basePath: overlays/
+templateBasePath: templates/
+propertySchema:
+  replicas:
+    type: int
+    default: 3
+addons:
+  monitoring:
+    path: addons/monitoring
+`,
+				"addons/monitoring/manifest.yaml": `name: monitoring
+properties:
+  host:
+    type: string
+files:
+  - kustomization.yaml
+`,
+				"addons/monitoring/kustomization.yaml": "host: {{ .Properties.host }}\nreplicas: {{ .ClusterProperties.replicas }}\n",
+				"templates/base/manifest.yaml":         "name: base\n",
+			},
+			want: []Finding{},
+		},
+		{
+			name: "unknown fields and duplicate keys",
+			files: map[string]string{
+				"PROJECT.yaml": `basePath: overlays/
+basePaht: overlays/
+environments:
+  dev:
+    stages:
+      test:
+        clusters:
+          hugi:
+            lables: {}
+            properties:
+              a: 1
+              a: 2
+`,
+			},
+			want: []Finding{
+				{File: "PROJECT.yaml", Line: 2, Column: 1, Path: "basePaht", Rule: RuleUnknownField, Severity: SeverityError, Message: "unknown field basePaht"},
+				{File: "PROJECT.yaml", Line: 9, Column: 13, Path: "environments.dev.stages.test.clusters.hugi.lables", Rule: RuleUnknownField, Severity: SeverityError, Message: "unknown field lables"},
+				{File: "PROJECT.yaml", Line: 12, Column: 15, Path: "environments.dev.stages.test.clusters.hugi.properties.a", Rule: RuleDuplicateKey, Severity: SeverityError, Message: "key a is defined more than once"},
+			},
+		},
+		{
+			name: "invalid property definitions",
+			files: map[string]string{
+				"PROJECT.yaml": `propertySchema:
+  replicas:
+    type: integer
+  tier:
+    type: enum
+    enum: [gold]
+    default: bronze
+    requiredAt: region
+  tags:
+    type: list
+    items:
+      descriptionL: tag
+  zone:
+    type: string
+    pattern: "["
+    default: a
+`,
+			},
+			want: []Finding{
+				{File: "PROJECT.yaml", Line: 2, Column: 3, Path: "propertySchema.replicas", Rule: RuleUnusedProperty, Severity: SeverityWarning, Message: "property replicas is not used by any template or addon file"},
+				{File: "PROJECT.yaml", Line: 3, Column: 11, Path: "propertySchema.replicas.type", Rule: RuleInvalidType, Severity: SeverityError, Message: `unknown property type "integer"`},
+				{File: "PROJECT.yaml", Line: 4, Column: 3, Path: "propertySchema.tier", Rule: RuleUnusedProperty, Severity: SeverityWarning, Message: "property tier is not used by any template or addon file"},
+				{File: "PROJECT.yaml", Line: 7, Column: 14, Path: "propertySchema.tier.default", Rule: RuleInvalidDefault, Severity: SeverityError, Message: "default does not match the property definition: value bronze is not one of [gold]"},
+				{File: "PROJECT.yaml", Line: 8, Column: 17, Path: "propertySchema.tier.requiredAt", Rule: RuleInvalidValue, Severity: SeverityError, Message: "unknown level region"},
+				{File: "PROJECT.yaml", Line: 9, Column: 3, Path: "propertySchema.tags", Rule: RuleUnusedProperty, Severity: SeverityWarning, Message: "property tags is not used by any template or addon file"},
+				{File: "PROJECT.yaml", Line: 12, Column: 7, Path: "propertySchema.tags.items.descriptionL", Rule: RuleUnknownField, Severity: SeverityError, Message: "unknown field descriptionL"},
+				{File: "PROJECT.yaml", Line: 12, Column: 7, Path: "propertySchema.tags.items", Rule: RuleInvalidType, Severity: SeverityError, Message: "property has no type"},
+				{File: "PROJECT.yaml", Line: 13, Column: 3, Path: "propertySchema.zone", Rule: RuleUnusedProperty, Severity: SeverityWarning, Message: "property zone is not used by any template or addon file"},
+				{File: "PROJECT.yaml", Line: 15, Column: 14, Path: "propertySchema.zone.pattern", Rule: RuleInvalidValue, Severity: SeverityError, Message: "invalid pattern: error parsing regexp: missing closing ]: `[`"},
+			},
+		},
+		{
+			name: "addons and templates",
+			files: map[string]string{
+				"PROJECT.yaml": `templateBasePath: templates/
+addons:
+  first:
+    path: addons/first
+  second:
+    path: addons/second
+  missing:
+    path: addons/missing
+  first:
+    path: addons/first
+`,
+				"addons/first/manifest.yaml":  "name: shared\nfiles:\n  - values.yaml\n",
+				"addons/second/manifest.yml":  "name: shared\n",
+				"templates/a/manifest.yaml":   "name: base\nscope: region\n",
+				"templates/b/manifest.yaml":   "name: base\n",
+				"templates/c/manifest.yaml":   "name: [broken\n",
+				"templates/d/other/file.yaml": "",
+			},
+			want: []Finding{
+				{File: "PROJECT.yaml", Line: 8, Column: 11, Path: "addons.missing.path", Rule: RuleMissingFile, Severity: SeverityError, Message: "addon missing: no manifest file found in addons/missing"},
+				{File: "PROJECT.yaml", Line: 9, Column: 3, Path: "addons.first", Rule: RuleDuplicateAddon, Severity: SeverityError, Message: "key first is defined more than once"},
+				{File: "addons/first/manifest.yaml", Line: 3, Column: 5, Path: "files[0]", Rule: RuleMissingFile, Severity: SeverityError, Message: "file values.yaml does not exist"},
+				{File: "addons/second/manifest.yml", Line: 1, Column: 7, Path: "name", Rule: RuleDuplicateAddon, Severity: SeverityError, Message: "addon name shared is already used by addon first"},
+				{File: "templates/a/manifest.yaml", Line: 2, Column: 8, Path: "scope", Rule: RuleInvalidValue, Severity: SeverityError, Message: "unknown scope region"},
+				{File: "templates/b/manifest.yaml", Line: 1, Column: 7, Path: "name", Rule: RuleDuplicateTemplate, Severity: SeverityError, Message: "template name base is already used by templates/a/manifest.yaml"},
+				{File: "templates/c/manifest.yaml", Rule: RuleInvalidYAML, Severity: SeverityError, Message: "yaml: line 1: did not find expected ',' or ']'"},
+			},
+		},
+		{
+			name: "names",
+			files: map[string]string{
+				"PROJECT.yaml": `naming:
+  stages:
+    reserved:
+      - default
+  clusters:
+    pattern: "["
+environments:
+  Dev:
+    stages:
+      default:
+        clusters:
+          hugi: {}
+  prod:
+    stages:
+      prod:
+        clusters:
+          hugi: {}
+          odin_1: {}
+`,
+			},
+			want: []Finding{
+				{File: "PROJECT.yaml", Line: 6, Column: 14, Path: "naming.clusters.pattern", Rule: RuleInvalidValue, Severity: SeverityError, Message: "invalid naming pattern: error parsing regexp: missing closing ]: `[`"},
+				{File: "PROJECT.yaml", Line: 8, Column: 3, Path: "environments.Dev", Rule: RuleInvalidName, Severity: SeverityError, Message: `environment "Dev" must be a DNS-1123 label: lowercase alphanumeric characters or '-', starting and ending with an alphanumeric character and at most 63 characters`},
+				{File: "PROJECT.yaml", Line: 10, Column: 7, Path: "environments.Dev.stages.default", Rule: RuleInvalidName, Severity: SeverityError, Message: `stage "default" is a reserved name`},
+			},
+		},
+		{
+			name: "cluster names",
+			files: map[string]string{
+				"PROJECT.yaml": `environments:
+  dev:
+    stages:
+      dev:
+        clusters:
+          hugi: {}
+      qa:
+        clusters:
+          hugi: {}
+          odin_1: {}
+`,
+			},
+			want: []Finding{
+				{File: "PROJECT.yaml", Line: 6, Column: 11, Path: "environments.dev.stages.dev.clusters.hugi", Rule: RuleInvalidName, Severity: SeverityError, Message: `cluster "hugi" is already used by a cluster in dev/qa`},
+				{File: "PROJECT.yaml", Line: 9, Column: 11, Path: "environments.dev.stages.qa.clusters.hugi", Rule: RuleInvalidName, Severity: SeverityError, Message: `cluster "hugi" is already used by a cluster in dev/dev`},
+				{File: "PROJECT.yaml", Line: 10, Column: 11, Path: "environments.dev.stages.qa.clusters.odin_1", Rule: RuleInvalidName, Severity: SeverityError, Message: `cluster "odin_1" must be a DNS-1123 label: lowercase alphanumeric characters or '-', starting and ending with an alphanumeric character and at most 63 characters`},
+			},
+		},
+		{
+			name: "property references",
+			files: map[string]string{
+				"PROJECT.yaml": `templateBasePath: templates/
+propertySchema:
+  domain:
+    type: string
+  interpolated:
+    type: string
+  unused:
+    type: string
+addons:
+  monitoring:
+    path: addons/monitoring
+environments:
+  dev:
+    properties:
+      name: "${properties.interpolated}"
+`,
+				"addons/monitoring/manifest.yaml": `name: monitoring
+properties:
+  host:
+    type: string
+    default: "grafana.{{ .Properties.domain }}"
+  unused:
+    type: string
+files:
+  - ./
+`,
+				"addons/monitoring/values.yaml":       "host: {{ .Properties.host }}\nport: {{ .Properties.port }}\nregion: {{ .ClusterProperties.region }}\n",
+				"addons/monitoring/broken.yaml":       "{{ .Properties.host ",
+				"templates/base/manifest.yaml":        "name: base\nproperties:\n  revision:\n    type: string\nfiles:\n  - values.yaml\n",
+				"templates/base/values.yaml":          "{{ .Properties.revision }}\n{{ .Addons.logging.Enabled }}\n{{ .Addons.monitoring.Properties.url }}\n",
+				"templates/base/overrides/dev/a.yaml": "{{ toYaml .Addons }}",
+			},
+			want: []Finding{
+				{File: "PROJECT.yaml", Line: 7, Column: 3, Path: "propertySchema.unused", Rule: RuleUnusedProperty, Severity: SeverityWarning, Message: "property unused is not used by any template or addon file"},
+				{File: "addons/monitoring/broken.yaml", Rule: RuleInvalidTemplate, Severity: SeverityError, Message: "template: addons/monitoring/broken.yaml:1: unclosed action"},
+				{File: "addons/monitoring/manifest.yaml", Line: 6, Column: 3, Path: "properties.unused", Rule: RuleUnusedProperty, Severity: SeverityWarning, Message: "property unused is not used by any file of addon monitoring"},
+				{File: "addons/monitoring/values.yaml", Line: 2, Column: 21, Rule: RuleUndeclaredProperty, Severity: SeverityWarning, Message: "property port is not declared in the manifest of addon monitoring"},
+				{File: "addons/monitoring/values.yaml", Line: 3, Column: 30, Rule: RuleUndeclaredProperty, Severity: SeverityWarning, Message: "property region is not declared in the property schema or a template manifest"},
+				{File: "templates/base/values.yaml", Line: 2, Column: 11, Rule: RuleUndeclaredAddon, Severity: SeverityWarning, Message: "addon logging is not declared in the project"},
+				{File: "templates/base/values.yaml", Line: 3, Column: 11, Rule: RuleUndeclaredProperty, Severity: SeverityWarning, Message: "property url is not declared in the manifest of addon monitoring"},
+			},
+		},
+		{
+			name: "property references in helpers and interpolations",
+			files: map[string]string{
+				"PROJECT.yaml": `helpersPath: helpers/
+propertySchema:
+  region:
+    type: string
+  tier:
+    type: string
+  tierName:
+    type: string
+  labels:
+    type: string
+environments:
+  dev:
+    properties:
+      name: "${properties.tierName}"
+      team: "${properties.labels.team}"
+`,
+				"helpers/names.tpl":  `{{ define "region" }}{{ .Properties.region }}{{ end }}`,
+				"helpers/broken.tpl": `{{ define "broken" }}`,
+			},
+			want: []Finding{
+				{File: "PROJECT.yaml", Line: 5, Column: 3, Path: "propertySchema.tier", Rule: RuleUnusedProperty, Severity: SeverityWarning, Message: "property tier is not used by any template or addon file"},
+				{File: "helpers/broken.tpl", Rule: RuleInvalidTemplate, Severity: SeverityError, Message: "template: helpers/broken.tpl:1: unexpected EOF"},
+			},
+		},
+		{
+			name: "plaintext secrets",
+			files: map[string]string{
+				"PROJECT.yaml": `propertySchema:
+  token:
+    type: secret
+addons:
+  monitoring:
+    path: addons/monitoring
+environments:
+  dev:
+    properties:
+      token: ENC[secretbox,AAAA]
+    stages:
+      dev:
+        clusters:
+          hugi:
+            properties:
+              token: plain
+            addons:
+              monitoring:
+                properties:
+                  password: plain
+`,
+				"addons/monitoring/manifest.yaml": "name: monitoring\nproperties:\n  password:\n    type: secret\nfiles:\n  - values.yaml\n",
+				"addons/monitoring/values.yaml":   "{{ .Properties.password }}{{ .ClusterProperties.token }}",
+			},
+			want: []Finding{
+				{File: "PROJECT.yaml", Line: 16, Column: 22, Path: "environments.dev.stages.dev.clusters.hugi.properties.token", Rule: RulePlaintextSecret, Severity: SeverityWarning, Message: "secret property token is stored in plain text"},
+				{File: "PROJECT.yaml", Line: 20, Column: 29, Path: "environments.dev.stages.dev.clusters.hugi.addons.monitoring.properties.password", Rule: RulePlaintextSecret, Severity: SeverityWarning, Message: "secret property password is stored in plain text"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			wd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = os.Chdir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { os.Chdir(wd) })
+
+			got, err := Project("PROJECT.yaml")
+			if err != nil {
+				t.Fatalf("Project() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Project() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/project"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
+	yamlv3 "sigs.k8s.io/yaml/goyaml.v3"
+)
+
+// declaration is the location a property has been declared at
+type declaration struct {
+	file string
+	node *yamlv3.Node
+	path string
+}
+
+// usage collects the properties that are used by the template files
+type usage struct {
+	keys map[string]bool
+	// all is true if the whole properties are accessed, e.g. toYaml .Properties
+	all bool
+}
+
+func (u *usage) use(key string) {
+	if key == "" {
+		u.all = true
+		return
+	}
+	u.keys[key] = true
+}
+
+func (u *usage) used(key string) bool {
+	return u.all || u.keys[key]
+}
+
+// lintReferences checks the property and addon references of all template and addon files
+// Cluster properties are declared by the property schema and the template manifests, addon properties by the addon manifest
+func (l *linter) lintReferences(file string, root *yamlv3.Node, config *project.ProjectConfig, addons map[string]*lintedManifest, templates []*lintedManifest) {
+	clusterDeclared := map[string][]declaration{}
+	for key := range config.PropertySchema {
+		path := joinPath("propertySchema", key)
+		clusterDeclared[key] = append(clusterDeclared[key], declaration{file: file, node: lookupKey(root, path), path: path})
+	}
+	for _, tm := range templates {
+		for key := range tm.Properties {
+			path := joinPath("properties", key)
+			clusterDeclared[key] = append(clusterDeclared[key], declaration{file: tm.path, node: lookupKey(tm.node, path), path: path})
+		}
+	}
+
+	clusterUsage := &usage{keys: map[string]bool{}}
+	addonUsage := map[string]*usage{}
+	for name := range addons {
+		addonUsage[name] = &usage{keys: map[string]bool{}}
+	}
+
+	checkClusterProperty := func(fpath string, ref template.Reference) {
+		clusterUsage.use(ref.Key)
+		if ref.Key != "" && clusterDeclared[ref.Key] == nil {
+			l.addReference(fpath, ref, RuleUndeclaredProperty, "property %s is not declared in the property schema or a template manifest", ref.Key)
+		}
+	}
+	checkAddon := func(fpath string, ref template.Reference) {
+		if ref.Key == "" {
+			return
+		}
+		if _, ok := config.Addons[ref.Key]; !ok {
+			l.addReference(fpath, ref, RuleUndeclaredAddon, "addon %s is not declared in the project", ref.Key)
+			return
+		}
+		tm, ok := addons[ref.Key]
+		if !ok || ref.Property == "" {
+			return
+		}
+		addonUsage[ref.Key].use(ref.Property)
+		if _, ok := tm.Properties[ref.Property]; !ok {
+			l.addReference(fpath, ref, RuleUndeclaredProperty, "property %s is not declared in the manifest of addon %s", ref.Property, ref.Key)
+		}
+	}
+
+	for _, tm := range templates {
+		for fpath, refs := range l.manifestReferences(tm) {
+			for _, ref := range refs {
+				switch ref.Field {
+				case template.ReferenceProperties:
+					checkClusterProperty(fpath, ref)
+				case template.ReferenceAddons:
+					checkAddon(fpath, ref)
+				}
+			}
+		}
+	}
+
+	for _, name := range utils.SortStringSlice(utils.MapKeysToList(addons)) {
+		tm := addons[name]
+		for fpath, refs := range l.manifestReferences(tm) {
+			for _, ref := range refs {
+				switch ref.Field {
+				case template.ReferenceProperties:
+					addonUsage[name].use(ref.Key)
+					if ref.Key != "" && !hasProperty(tm.Properties, ref.Key) {
+						l.addReference(fpath, ref, RuleUndeclaredProperty, "property %s is not declared in the manifest of addon %s", ref.Key, name)
+					}
+				case template.ReferenceClusterProperties:
+					checkClusterProperty(fpath, ref)
+				case template.ReferenceAddons:
+					checkAddon(fpath, ref)
+				}
+			}
+		}
+		// templated defaults are rendered with the cluster properties
+		for _, property := range tm.Properties {
+			if !property.HasTemplatedDefault() {
+				continue
+			}
+			refs, err := template.FindReferences(tm.path, property.Default.(string))
+			if err != nil {
+				continue
+			}
+			for _, ref := range refs {
+				if ref.Field == template.ReferenceProperties {
+					clusterUsage.use(ref.Key)
+				}
+			}
+		}
+	}
+
+	// the define blocks of the helpers are used by templates and addons, so their properties are used by both
+	for fpath, refs := range l.helperReferences(file, root, config.HelpersPath) {
+		for _, ref := range refs {
+			switch ref.Field {
+			case template.ReferenceProperties:
+				clusterUsage.use(ref.Key)
+				for _, u := range addonUsage {
+					u.use(ref.Key)
+				}
+			case template.ReferenceClusterProperties:
+				clusterUsage.use(ref.Key)
+			case template.ReferenceAddons:
+				checkAddon(fpath, ref)
+			}
+		}
+	}
+
+	// properties can be referenced by the interpolation of other property values as well
+	projectContent, _ := os.ReadFile(file)
+	for _, key := range utils.SortStringSlice(utils.MapKeysToList(clusterDeclared)) {
+		if clusterUsage.used(key) || interpolated(string(projectContent), key) {
+			continue
+		}
+		for _, decl := range clusterDeclared[key] {
+			l.add(decl.file, decl.node, decl.path, RuleUnusedProperty, "property %s is not used by any template or addon file", key)
+		}
+	}
+	for _, name := range utils.SortStringSlice(utils.MapKeysToList(addons)) {
+		tm := addons[name]
+		for _, key := range utils.SortStringSlice(utils.MapKeysToList(tm.Properties)) {
+			if addonUsage[name].used(key) {
+				continue
+			}
+			path := joinPath("properties", key)
+			l.add(tm.path, lookupKey(tm.node, path), path, RuleUnusedProperty, "property %s is not used by any file of addon %s", key, name)
+		}
+	}
+}
+
+// manifestReferences returns the references of all files of the manifest by file
+// Files that are not valid templates are added to the findings
+func (l *linter) manifestReferences(tm *lintedManifest) map[string][]template.Reference {
+	result := map[string][]template.Reference{}
+	files, err := template.SourceFiles(filepath.Dir(tm.path), tm.Files)
+	if err != nil {
+		l.add(tm.path, nil, "files", RuleMissingFile, "%v", err)
+		return result
+	}
+	for _, fpath := range files {
+		refs, err := template.FindFileReferences(fpath)
+		if err != nil {
+			l.add(fpath, nil, "", RuleInvalidTemplate, "%v", err)
+			continue
+		}
+		result[fpath] = refs
+	}
+	return result
+}
+
+// helperReferences returns the references of all files in the helpers directory by file
+// Files that are not valid templates are added to the findings
+func (l *linter) helperReferences(file string, root *yamlv3.Node, dir string) map[string][]template.Reference {
+	result := map[string][]template.Reference{}
+	if dir == "" {
+		return result
+	}
+	if _, err := os.Stat(dir); err != nil {
+		l.add(file, lookup(root, "helpersPath"), "helpersPath", RuleMissingFile, "%v", err)
+		return result
+	}
+	_ = filepath.WalkDir(dir, func(fpath string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		refs, err := template.FindFileReferences(fpath)
+		if err != nil {
+			l.add(fpath, nil, "", RuleInvalidTemplate, "%v", err)
+			return nil
+		}
+		result[fpath] = refs
+		return nil
+	})
+	return result
+}
+
+// interpolated checks if the property is referenced as a whole by an interpolation, e.g. ${properties.<key>} or ${properties.<key>.<field>}
+func interpolated(content, key string) bool {
+	return strings.Contains(content, "${properties."+key+"}") || strings.Contains(content, "${properties."+key+".")
+}
+
+// addReference adds a finding at the position of the reference
+func (l *linter) addReference(file string, ref template.Reference, rule Rule, format string, args ...any) {
+	l.findings = append(l.findings, Finding{
+		File:     file,
+		Line:     ref.Line,
+		Column:   ref.Column,
+		Rule:     rule,
+		Severity: rule.severity(),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// hasProperty checks if the property has been declared
+func hasProperty(properties map[string]template.Property, key string) bool {
+	_, ok := properties[key]
+	return ok
+}
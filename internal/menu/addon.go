@@ -2,11 +2,13 @@ package menu
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"io"
 
 	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/cli"
 	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/project"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
 	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
 	"github.com/manifoldco/promptui"
 )
@@ -15,6 +17,11 @@ type addonClusterMenu struct {
 	writer io.Writer
 	reader *bufio.Reader
 	config *project.ProjectConfig
+	// origin is the level of the managed addon handler
+	origin project.PropertyOrigin
+	// environment and stage are the parents of the managed addon handler, if any
+	environment string
+	stage       string
 }
 
 func (a *addonClusterMenu) menuManageAddons(ah project.AddonHandler, skipAddonValidation bool) error {
@@ -60,7 +67,7 @@ func (a *addonClusterMenu) templateManageAddons(ah project.AddonHandler) *prompt
 				resultString := "--------------------------------\nDetails:\n"
 				resultString += fmt.Sprintf("\tDescription: %s\n", a.config.ParsedAddons[addonName].Description)
 				resultString += fmt.Sprintf("\tGroup: %s\n", a.config.ParsedAddons[addonName].Group)
-				resultString += fmt.Sprintf("\tEnabled: %v | Default: %v\n", ah.GetAddon(addonName).IsEnabled(), a.config.Addons[addonName].DefaultEnabled)
+				resultString += fmt.Sprintf("\tState: %s | Default: %v\n", ah.GetAddon(addonName).State(), a.config.Addons[addonName].DefaultEnabled)
 				return resultString
 			}
 			return funcmap
@@ -70,10 +77,16 @@ func (a *addonClusterMenu) templateManageAddons(ah project.AddonHandler) *prompt
 
 func (a *addonClusterMenu) menuAddonSettings(ah project.AddonHandler, addon string) error {
 	for {
-		selectOptions := []string{"Enable", "Done"}
-		if ah.IsAddonEnabled(addon) {
-			selectOptions = []string{"Disable", "Settings", "Done"}
+		selectOptions := []string{}
+		switch ah.GetAddon(addon).State() {
+		case project.AddonStateEnabled:
+			selectOptions = append(selectOptions, "Disable", "Inherit")
+		case project.AddonStateDisabled:
+			selectOptions = append(selectOptions, "Enable", "Inherit")
+		default:
+			selectOptions = append(selectOptions, "Enable", "Disable")
 		}
+		selectOptions = append(selectOptions, "Settings", "Done")
 
 		prompt := promptui.Select{
 			Label: "Settings",
@@ -89,7 +102,13 @@ func (a *addonClusterMenu) menuAddonSettings(ah project.AddonHandler, addon stri
 			ah.EnableAddon(addon)
 		case "Disable":
 			ah.DisableAddon(addon)
+		case "Inherit":
+			ah.InheritAddon(addon)
 		case "Settings":
+			if ah.GetAddon(addon) == nil {
+				// properties can be set without changing the enablement
+				ah.GetAddons()[addon] = &project.ClusterAddon{}
+			}
 			err := a.menuAddonProperties(ah, addon)
 			if err != nil {
 				return err
@@ -128,7 +147,8 @@ func (a *addonClusterMenu) menuAddonProperties(ah project.AddonHandler, addon st
 			break
 		}
 
-		value, err := cli.UntypedQuestion(a.writer, a.reader, "Value", fmt.Sprintf("%v", ah.GetAddon(addon).Properties[result]), func(s any) error {
+		property := a.config.ParsedAddons[addon].Properties[result]
+		value, err := cli.UntypedQuestion(a.writer, a.reader, "Value", propertyPromptDefault(property, ah.GetAddon(addon).Properties[result]), func(s any) error {
 			if s == nil {
 				return fmt.Errorf("value cannot be empty")
 			}
@@ -139,11 +159,23 @@ func (a *addonClusterMenu) menuAddonProperties(ah project.AddonHandler, addon st
 			continue
 		}
 
-		value, err = a.config.ParsedAddons[addon].Properties[result].ParseValue(value)
+		if isMaskedSecret(property, value) {
+			// the secret has not been changed
+			continue
+		}
+
+		value, err = property.ParseValue(value)
 		if err != nil {
 			fmt.Println(utils.Red.Wrap("Value violates requirements, please try again"), err)
 			continue
 		}
+		if property.Type == template.PropertyTypeSecret {
+			value, err = a.config.EncryptSecret(value)
+			if err != nil {
+				fmt.Println(utils.Red.Wrap("Failed to encrypt the secret"), err)
+				continue
+			}
+		}
 		ah.GetAddon(addon).SetProperty(result, value)
 	}
 	return nil
@@ -164,12 +196,24 @@ func (a *addonClusterMenu) menuTemplateAddonProperties(ah project.AddonHandler,
 				resultString += fmt.Sprintf("\tDescription: %s\n", a.config.ParsedAddons[addon].Properties[selectValue].Description)
 				resultString += fmt.Sprintf("\tRequired: %v\n", a.config.ParsedAddons[addon].Properties[selectValue].Required)
 				resultString += fmt.Sprintf("\tType: %v\n", a.config.ParsedAddons[addon].Properties[selectValue].Type)
-				resultString += fmt.Sprintf("\tDefault: %v\n", a.config.ParsedAddons[addon].Properties[selectValue].Default)
-				data := ah.GetAddon(addon).Properties[selectValue]
-				if data == nil {
-					data = a.config.ParsedAddons[addon].Properties[selectValue].Default
+				resultString += fmt.Sprintf("\tDefault: %v\n", propertyPromptDefault(a.config.ParsedAddons[addon].Properties[selectValue], a.config.ParsedAddons[addon].Properties[selectValue].Default))
+				if a.config.ParsedAddons[addon].Properties[selectValue].HasTemplatedDefault() && a.config.ParsedAddons[addon].Properties[selectValue].Type != template.PropertyTypeSecret {
+					resultString += fmt.Sprintf("\tComputed Default: %v\n", a.computedDefault(ah, addon, selectValue))
+				}
+				if enum := a.config.ParsedAddons[addon].Properties[selectValue].Enum; len(enum) > 0 {
+					resultString += fmt.Sprintf("\tAllowed: %v\n", enum)
 				}
-				resultString += fmt.Sprintf("\tValue: %v\n", data)
+				if format := a.config.ParsedAddons[addon].Properties[selectValue].Format; format != "" {
+					resultString += fmt.Sprintf("\tFormat: %v\n", format)
+				}
+				if generate := a.config.ParsedAddons[addon].Properties[selectValue].Generate; generate != nil {
+					resultString += fmt.Sprintf("\tGenerated: %v\n", generate.Strategy)
+				}
+				layers := append(a.config.AddonPropertyLayersAt(addon, a.environment, a.stage), project.PropertyLayer{
+					Origin:     a.origin,
+					Properties: ah.GetAddon(addon).Properties,
+				})
+				resultString += formatPropertyOrigin(layers.Explain(a.config.ParsedAddons[addon].Properties), selectValue)
 				return resultString
 			}
 			return funcmap
@@ -177,6 +221,41 @@ func (a *addonClusterMenu) menuTemplateAddonProperties(ah project.AddonHandler,
 	}
 }
 
+// computedDefault renders the templated default of the addon property for the cluster
+// The default can only be computed for clusters, because it depends on the cluster context
+func (a *addonClusterMenu) computedDefault(ah project.AddonHandler, addon, property string) string {
+	cluster, ok := ah.(*project.Cluster)
+	if !ok {
+		return "rendered for each cluster"
+	}
+	properties, err := cluster.ResolvedProperties(a.config, a.environment, a.stage)
+	if err != nil {
+		return err.Error()
+	}
+	defaults, err := cluster.AddonDefaults(a.config, addon, a.environment, a.stage, properties)
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("%v", defaults[property])
+}
+
+// formatPropertyValue formats the property value, so that it can be parsed again if the user keeps it
+// Lists and maps are formatted as json, which is a subset of yaml
+func formatPropertyValue(value any) any {
+	switch value.(type) {
+	case nil:
+		return nil
+	case []any, map[string]any:
+		bts, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprintf("%v", value)
+		}
+		return string(bts)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
 type addonMenu struct {
 	writer io.Writer
 	reader *bufio.Reader
@@ -26,7 +26,7 @@ func (c *clusterMenu) menuCreateCluster(env, stage string) (*project.Cluster, er
 		if c.config.HasCluster(env, stage, s) {
 			return fmt.Errorf("cluster already exists")
 		}
-		return nil
+		return c.config.ValidateClusterName(env, stage, s)
 	})
 	if err != nil {
 		return nil, err
@@ -35,15 +35,24 @@ func (c *clusterMenu) menuCreateCluster(env, stage string) (*project.Cluster, er
 	cluster := &project.Cluster{
 		Name:       clusterName,
 		Addons:     map[string]*project.ClusterAddon{},
-		Properties: map[string]string{},
+		Properties: map[string]any{},
 	}
 	cluster.SetDefaultAddons(c.config)
+	err = c.config.GenerateProperties(env, stage, cluster)
+	if err != nil {
+		return nil, err
+	}
 
 	err = c.menuSettings(env, stage, cluster)
 	if err != nil {
 		return nil, err
 	}
 
+	// addons enabled in the settings may define generated properties as well
+	err = c.config.GenerateProperties(env, stage, cluster)
+	if err != nil {
+		return nil, err
+	}
 	return cluster, nil
 }
 
@@ -52,7 +61,7 @@ func (c *clusterMenu) menuSettings(env, stage string, cluster *project.Cluster)
 	for {
 		prompt := promptui.Select{
 			Label: "Settings",
-			Items: []string{"Addons", "Properties", "Done"},
+			Items: []string{"Addons", "Properties", "Templates", "Labels", "Done"},
 		}
 		_, result, err := prompt.Run()
 		if err != nil {
@@ -62,9 +71,12 @@ func (c *clusterMenu) menuSettings(env, stage string, cluster *project.Cluster)
 		switch result {
 		case "Addons":
 			addon := addonClusterMenu{
-				writer: c.writer,
-				reader: c.reader,
-				config: c.config,
+				writer:      c.writer,
+				reader:      c.reader,
+				config:      c.config,
+				origin:      project.PropertyOriginCluster,
+				environment: env,
+				stage:       stage,
 			}
 
 			err := addon.menuManageAddons(cluster, true)
@@ -72,13 +84,47 @@ func (c *clusterMenu) menuSettings(env, stage string, cluster *project.Cluster)
 				return err
 			}
 		case "Properties":
-			properties, err := c.menuClusterSettingsProperties(env, stage, cluster)
+			pm := propertyMenu{
+				writer: c.writer,
+				reader: c.reader,
+				config: c.config,
+			}
+			properties, err := pm.menuManageProperties(project.PropertyLevelCluster, c.config.PropertyLayersAt(env, stage), cluster.Properties)
 			if err != nil {
 				return err
 			}
 			cluster.Properties = properties
+		case "Templates":
+			tm := templateMenu{
+				writer: c.writer,
+				reader: c.reader,
+				config: c.config,
+			}
+			err := tm.menuManageTemplates(cluster)
+			if err != nil {
+				return err
+			}
+		case "Labels":
+			labels, err := c.menuClusterSettingsLabels(cluster)
+			if err != nil {
+				return err
+			}
+			cluster.Labels = labels
 		case "Done":
-			return nil
+			// the cluster can only be saved if it complies with the policies of the project
+			violations, err := c.config.CheckClusterPolicies(env, stage, cluster)
+			if err != nil {
+				// the cluster is not saved if its compliance cannot be verified
+				fmt.Fprintln(c.writer, utils.Red.Wrap("An error occurred while checking the policies:"), err)
+				continue
+			}
+			if len(violations) == 0 {
+				return nil
+			}
+			fmt.Fprintln(c.writer, utils.Red.Wrap("The cluster violates the policies of the project:"))
+			for _, v := range violations {
+				fmt.Fprintf(c.writer, "  %s (%s)\n", v.Message, v.Policy)
+			}
 		default:
 			return fmt.Errorf("invalid option %s", result)
 		}
@@ -92,7 +138,15 @@ func (c *clusterMenu) menuUpdateCluster(envName, stageName, clusterName string)
 		cluster.Name = clusterName
 	}
 	cluster.SetDefaultAddons(c.config)
-	err := c.menuSettings(envName, stageName, cluster)
+	err := c.config.GenerateProperties(envName, stageName, cluster)
+	if err != nil {
+		return nil, err
+	}
+	err = c.menuSettings(envName, stageName, cluster)
+	if err != nil {
+		return nil, err
+	}
+	err = c.config.GenerateProperties(envName, stageName, cluster)
 	if err != nil {
 		return nil, err
 	}
@@ -111,38 +165,36 @@ func (c *clusterMenu) menuDeleteCluster(env, stage, cluster string) (*project.Cl
 	return c.config.GetCluster(env, stage, cluster), nil
 }
 
-func (c *clusterMenu) menuClusterSettingsProperties(env, stage string, cluster *project.Cluster) (map[string]string, error) {
-	clusterProperties := map[string]string{}
+func (c *clusterMenu) menuClusterSettingsLabels(cluster *project.Cluster) (map[string]string, error) {
+	labels := utils.MergeMaps(cluster.Labels)
 	for {
-		properties := utils.MergeMaps(c.config.EnvStageProperty(env, stage), cluster.Properties, clusterProperties)
-
 		prompt := promptui.SelectWithAdd{
-			Label:    "Properties",
-			Items:    append(utils.SortStringSlice(utils.MapKeysToList(properties)), "Done"),
-			AddLabel: "Create Property",
+			Label:    "Labels",
+			Items:    append(utils.SortStringSlice(utils.MapKeysToList(labels)), "Done"),
+			AddLabel: "Create Label",
 		}
 		_, result, err := prompt.Run()
 		if err != nil {
 			return nil, err
 		}
 		if result == "" {
-			return nil, fmt.Errorf("property key cannot be empty")
+			return nil, fmt.Errorf("label key cannot be empty")
 		}
 		if result == "Done" {
 			// user is done
 			break
 		}
 
-		val, err := cli.StringQuestion(c.writer, c.reader, "Property Value", properties[result], func(s string) error {
+		val, err := cli.StringQuestion(c.writer, c.reader, "Label Value", labels[result], func(s string) error {
 			if s == "" {
-				return fmt.Errorf("property value cannot be empty")
+				return fmt.Errorf("label value cannot be empty")
 			}
 			return nil
 		})
 		if err != nil {
 			return nil, err
 		}
-		clusterProperties[result] = val
+		labels[result] = val
 	}
-	return clusterProperties, nil
+	return labels, nil
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/cli"
 	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/project"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/secret"
 	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
 	"github.com/manifoldco/promptui"
 )
@@ -26,7 +27,7 @@ func (e *environmentMenu) menuCreateEnvironment() (*project.Environment, error)
 		if e.config.HasEnvironment(s) {
 			return fmt.Errorf("environment already exists")
 		}
-		return nil
+		return e.config.ValidateEnvironmentName(s)
 	})
 	if err != nil {
 		return nil, err
@@ -35,7 +36,7 @@ func (e *environmentMenu) menuCreateEnvironment() (*project.Environment, error)
 	environment := &project.Environment{
 		Name:       env,
 		Stages:     map[string]*project.Stage{},
-		Properties: map[string]string{},
+		Properties: map[string]any{},
 		Addons:     map[string]*project.ClusterAddon{},
 	}
 
@@ -64,7 +65,7 @@ func (e *environmentMenu) menuSettings(environment *project.Environment) error {
 	for {
 		prompt := promptui.Select{
 			Label: "Settings",
-			Items: []string{"Addons", "Properties", "Done"},
+			Items: []string{"Addons", "Properties", "Templates", "Sealed Secrets", "Done"},
 		}
 		_, result, err := prompt.Run()
 		if err != nil {
@@ -77,17 +78,43 @@ func (e *environmentMenu) menuSettings(environment *project.Environment) error {
 				writer: e.writer,
 				reader: e.reader,
 				config: e.config,
+				origin: project.PropertyOriginEnvironment,
 			}
 			err := addon.menuManageAddons(environment, true)
 			if err != nil {
 				return err
 			}
 		case "Properties":
-			properties, err := e.menuEnvironmentProperties(environment)
+			pm := propertyMenu{
+				writer: e.writer,
+				reader: e.reader,
+				config: e.config,
+			}
+			properties, err := pm.menuManageProperties(project.PropertyLevelEnvironment, e.config.PropertyLayersAt("", ""), environment.Properties)
 			if err != nil {
 				return err
 			}
 			environment.Properties = properties
+		case "Templates":
+			tm := templateMenu{
+				writer: e.writer,
+				reader: e.reader,
+				config: e.config,
+			}
+			err := tm.menuManageTemplates(environment)
+			if err != nil {
+				return err
+			}
+		case "Sealed Secrets":
+			certificate, err := cli.StringQuestion(e.writer, e.reader, "Sealed Secrets Certificate", environment.SealedSecretsCertificate, func(s string) error {
+				_, err := secret.LoadSealingCertificate(s)
+				return err
+			})
+			if err != nil {
+				fmt.Fprintln(e.writer, utils.Red.Wrap("Invalid certificate, please try again"), err)
+				continue
+			}
+			environment.SealedSecretsCertificate = certificate
 		case "Done":
 			return nil
 		default:
@@ -108,37 +135,3 @@ func (e *environmentMenu) menuDeleteEnvironment(envName string) (*project.Enviro
 	environment.Name = envName
 	return environment, errors.New("menuDeleteEnvironment not implemented")
 }
-
-func (e *environmentMenu) menuEnvironmentProperties(env *project.Environment) (map[string]string, error) {
-	envProperties := env.Properties
-	for {
-		prompt := promptui.SelectWithAdd{
-			Label:    "Properties",
-			Items:    append(utils.SortStringSlice(utils.MapKeysToList(envProperties)), "Done"),
-			AddLabel: "Create Property",
-		}
-		_, result, err := prompt.Run()
-		if err != nil {
-			return nil, err
-		}
-		if result == "" {
-			return nil, fmt.Errorf("property key cannot be empty")
-		}
-		if result == "Done" {
-			// user is done
-			break
-		}
-
-		val, err := cli.StringQuestion(e.writer, e.reader, "Property Value", envProperties[result], func(s string) error {
-			if s == "" {
-				return fmt.Errorf("property value cannot be empty")
-			}
-			return nil
-		})
-		if err != nil {
-			return nil, err
-		}
-		envProperties[result] = val
-	}
-	return envProperties, nil
-}
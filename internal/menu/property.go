@@ -0,0 +1,161 @@
+package menu
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/cli"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/project"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/secret"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
+	"github.com/manifoldco/promptui"
+)
+
+const (
+	propertyOptionCreate = "Create Property"
+)
+
+type propertyMenu struct {
+	writer io.Writer
+	reader *bufio.Reader
+	config *project.ProjectConfig
+}
+
+// menuManageProperties creates a context menu to manage the properties of an environment, stage or cluster
+// parents contains the property layers of the parents, which are used as default values
+func (p *propertyMenu) menuManageProperties(level project.PropertyLevel, parents project.PropertyLayers, properties map[string]any) (map[string]any, error) {
+	result := utils.MergeMaps(properties)
+	layers := append(parents, project.PropertyLayer{Origin: project.PropertyOrigin(level), Properties: result})
+	for {
+		merged := layers.Merge(p.config.PropertyDefinitions())
+		keys := utils.MapKeysToList(merged)
+		for key := range p.config.PropertySchema {
+			if _, ok := merged[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+
+		prompt := promptui.Select{
+			Label:     "Properties",
+			Items:     append(utils.SortStringSlice(keys), propertyOptionCreate, "Done"),
+			Templates: p.templateManageProperties(layers),
+			Size:      10,
+		}
+		_, key, err := prompt.Run()
+		if err != nil {
+			return nil, err
+		}
+		if key == "Done" {
+			err := p.config.CheckRequiredProperties(level, merged)
+			if err != nil {
+				fmt.Fprintln(p.writer, utils.Red.Wrap("Properties violate requirements, please try again"), err)
+				continue
+			}
+			// user is done
+			break
+		}
+		if key == propertyOptionCreate {
+			key, err = cli.StringQuestion(p.writer, p.reader, "Property Key", "", func(s string) error {
+				if s == "" {
+					return fmt.Errorf("property key cannot be empty")
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		definition := p.config.PropertyDefinitions()[key]
+		value, err := cli.UntypedQuestion(p.writer, p.reader, "Property Value", propertyPromptDefault(definition, merged[key]), func(s any) error {
+			if s == nil {
+				return fmt.Errorf("property value cannot be empty")
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintln(p.writer, utils.Red.Wrap("Value violates requirements, please try again"), err)
+			continue
+		}
+
+		if isMaskedSecret(definition, value) {
+			// the secret has not been changed
+			continue
+		}
+
+		value, err = p.config.ParsePropertyValue(key, value)
+		if err != nil {
+			fmt.Fprintln(p.writer, utils.Red.Wrap("Value violates requirements, please try again"), err)
+			continue
+		}
+		if definition.Type == template.PropertyTypeSecret {
+			value, err = p.config.EncryptSecret(value)
+			if err != nil {
+				fmt.Fprintln(p.writer, utils.Red.Wrap("Failed to encrypt the secret"), err)
+				continue
+			}
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+func (p *propertyMenu) templateManageProperties(layers project.PropertyLayers) *promptui.SelectTemplates {
+	return &promptui.SelectTemplates{
+		Label:   "{{ . }}",
+		Details: "{{ property . }}",
+		FuncMap: func() map[string]any {
+			funcmap := promptui.FuncMap
+			funcmap["property"] = func(key string) string {
+				if key == "Done" || key == propertyOptionCreate {
+					return ""
+				}
+				resultString := "--------------------------------\nDetails:\n"
+				if definition, ok := p.config.PropertySchema[key]; ok {
+					resultString += fmt.Sprintf("\tDescription: %s\n", definition.Description)
+					resultString += fmt.Sprintf("\tType: %v\n", definition.Type)
+					if definition.RequiredAt != "" || definition.Required {
+						resultString += fmt.Sprintf("\tRequired: %v\n", true)
+					}
+					if definition.Generate != nil {
+						resultString += fmt.Sprintf("\tGenerated: %v\n", definition.Generate.Strategy)
+					}
+				}
+				resultString += formatPropertyOrigin(layers.Explain(p.config.PropertyDefinitions()), key)
+				return resultString
+			}
+			return funcmap
+		}(),
+	}
+}
+
+// formatPropertyOrigin formats the effective value, origin and shadowed values of the property for the details panes
+func formatPropertyOrigin(explained []project.ExplainedProperty, key string) string {
+	for _, ep := range explained {
+		if ep.Key != key {
+			continue
+		}
+		resultString := fmt.Sprintf("\tValue: %v\n", ep.Value)
+		resultString += fmt.Sprintf("\tOrigin: %s\n", ep.Origin)
+		for _, shadowed := range ep.Shadowed {
+			resultString += fmt.Sprintf("\tShadowed: %v (%s)\n", shadowed.Value, shadowed.Origin)
+		}
+		return resultString
+	}
+	return "\tValue: <not set>\n"
+}
+
+// propertyPromptDefault returns the default of the value prompt, values of secret properties are masked
+func propertyPromptDefault(property template.Property, value any) any {
+	if property.Type == template.PropertyTypeSecret && value != nil {
+		return secret.Mask
+	}
+	return formatPropertyValue(value)
+}
+
+// isMaskedSecret checks if the value of the secret property has been kept as it is
+func isMaskedSecret(property template.Property, value any) bool {
+	return property.Type == template.PropertyTypeSecret && value == secret.Mask
+}
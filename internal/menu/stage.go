@@ -8,7 +8,6 @@ import (
 
 	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/cli"
 	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/project"
-	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
 	"github.com/manifoldco/promptui"
 )
 
@@ -26,7 +25,7 @@ func (s *stageMenu) menuCreateStage(env string) (*project.Stage, error) {
 		if s.config.GetEnvironment(env).HasStage(str) {
 			return fmt.Errorf("stage already exists")
 		}
-		return nil
+		return s.config.ValidateStageName(str)
 	})
 	if err != nil {
 		return nil, err
@@ -34,13 +33,13 @@ func (s *stageMenu) menuCreateStage(env string) (*project.Stage, error) {
 
 	stage := &project.Stage{
 		Name:       stageName,
-		Properties: map[string]string{},
+		Properties: map[string]any{},
 		Actions:    project.Actions{},
 		Clusters:   map[string]*project.Cluster{},
 		Addons:     map[string]*project.ClusterAddon{},
 	}
 
-	err = s.menuSettings(stage)
+	err = s.menuSettings(env, stage)
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +52,7 @@ func (s *stageMenu) menuUpdateStage(envName, stageName string) (*project.Stage,
 	if stage.Addons == nil {
 		stage.Addons = map[string]*project.ClusterAddon{}
 	}
-	err := s.menuSettings(stage)
+	err := s.menuSettings(envName, stage)
 	if err != nil {
 		return nil, err
 	}
@@ -61,11 +60,11 @@ func (s *stageMenu) menuUpdateStage(envName, stageName string) (*project.Stage,
 }
 
 // menuSettings creates a context menu to manage the settings of a cluster
-func (s *stageMenu) menuSettings(stage *project.Stage) error {
+func (s *stageMenu) menuSettings(env string, stage *project.Stage) error {
 	for {
 		prompt := promptui.Select{
 			Label: "Settings",
-			Items: []string{"Addons", "Properties", "Done"},
+			Items: []string{"Addons", "Properties", "Templates", "Done"},
 		}
 		_, result, err := prompt.Run()
 		if err != nil {
@@ -75,20 +74,37 @@ func (s *stageMenu) menuSettings(stage *project.Stage) error {
 		switch result {
 		case "Addons":
 			addon := addonClusterMenu{
-				writer: s.writer,
-				reader: s.reader,
-				config: s.config,
+				writer:      s.writer,
+				reader:      s.reader,
+				config:      s.config,
+				origin:      project.PropertyOriginStage,
+				environment: env,
 			}
 			err := addon.menuManageAddons(stage, true)
 			if err != nil {
 				return err
 			}
 		case "Properties":
-			properties, err := s.menuProperties(stage)
+			pm := propertyMenu{
+				writer: s.writer,
+				reader: s.reader,
+				config: s.config,
+			}
+			properties, err := pm.menuManageProperties(project.PropertyLevelStage, s.config.PropertyLayersAt(env, ""), stage.Properties)
 			if err != nil {
 				return err
 			}
 			stage.Properties = properties
+		case "Templates":
+			tm := templateMenu{
+				writer: s.writer,
+				reader: s.reader,
+				config: s.config,
+			}
+			err := tm.menuManageTemplates(stage)
+			if err != nil {
+				return err
+			}
 		case "Done":
 			return nil
 		default:
@@ -101,39 +117,3 @@ func (s *stageMenu) menuDeleteStage(env, stage string) error {
 	// TODO: menu is missing to delete the stage (cascade delete)
 	return errors.New("not implemented")
 }
-
-func (s *stageMenu) menuProperties(stage *project.Stage) (map[string]string, error) {
-	stageProperties := map[string]string{}
-	for {
-		properties := utils.MergeMaps(stage.Properties, stageProperties)
-
-		prompt := promptui.SelectWithAdd{
-			Label:    "Properties",
-			Items:    append(utils.SortStringSlice(utils.MapKeysToList(properties)), "Done"),
-			AddLabel: "Create Property",
-		}
-		_, result, err := prompt.Run()
-		if err != nil {
-			return nil, err
-		}
-		if result == "" {
-			return nil, fmt.Errorf("property key cannot be empty")
-		}
-		if result == "Done" {
-			// user is done
-			break
-		}
-
-		val, err := cli.StringQuestion(s.writer, s.reader, "Property Value", properties[result], func(s string) error {
-			if s == "" {
-				return fmt.Errorf("property value cannot be empty")
-			}
-			return nil
-		})
-		if err != nil {
-			return nil, err
-		}
-		stageProperties[result] = val
-	}
-	return stageProperties, nil
-}
@@ -0,0 +1,100 @@
+package menu
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/project"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
+	"github.com/manifoldco/promptui"
+)
+
+const (
+	templateOptionInherit = "Inherit"
+)
+
+type templateMenu struct {
+	writer io.Writer
+	reader *bufio.Reader
+	config *project.ProjectConfig
+}
+
+// menuManageTemplates creates a context menu to select the base templates that are rendered for the clusters
+func (t *templateMenu) menuManageTemplates(th project.TemplateHandler) error {
+	templates, err := template.LoadTemplateManifest(t.config.TemplateBasePath)
+	if err != nil {
+		return fmt.Errorf("failed to load base templates: %w", err)
+	}
+
+	manifests := map[string]template.TemplateManifest{}
+	for _, tmpl := range templates {
+		if tmpl.TemplateManifest.GetScope() != template.TemplateScopeCluster {
+			// only cluster templates can be selected
+			continue
+		}
+		manifests[tmpl.TemplateManifest.Name] = tmpl.TemplateManifest
+	}
+
+	for {
+		prompt := promptui.Select{
+			Label:     "Manage Templates",
+			Items:     append(utils.SortStringSlice(utils.MapKeysToList(manifests)), templateOptionInherit, "Done"),
+			Templates: t.templateManageTemplates(th, manifests),
+			Size:      10,
+		}
+		_, result, err := prompt.Run()
+		if err != nil {
+			return err
+		}
+
+		switch result {
+		case "Done":
+			return nil
+		case templateOptionInherit:
+			th.SetTemplates(nil)
+		default:
+			selected := slices.Clone(th.GetTemplates())
+			if selected == nil {
+				selected = []string{}
+			}
+			if idx := slices.Index(selected, result); idx != -1 {
+				selected = slices.Delete(selected, idx, idx+1)
+			} else {
+				selected = append(selected, result)
+			}
+			th.SetTemplates(utils.SortStringSlice(selected))
+		}
+	}
+}
+
+func (t *templateMenu) templateManageTemplates(th project.TemplateHandler, manifests map[string]template.TemplateManifest) *promptui.SelectTemplates {
+	return &promptui.SelectTemplates{
+		Label:   "{{ . }}",
+		Details: "{{ template . }}",
+		FuncMap: func() map[string]any {
+			funcmap := promptui.FuncMap
+			funcmap["template"] = func(templateName string) string {
+				if templateName == "Done" {
+					return ""
+				}
+				resultString := "--------------------------------\nDetails:\n"
+				if templateName == templateOptionInherit {
+					resultString += "\tReset the selection to the one of the parent\n"
+					return resultString
+				}
+				selection := "inherited"
+				if th.GetTemplates() != nil {
+					selection = fmt.Sprintf("%v", slices.Contains(th.GetTemplates(), templateName))
+				}
+				resultString += fmt.Sprintf("\tDescription: %s\n", manifests[templateName].Description)
+				resultString += fmt.Sprintf("\tSelector: %v\n", manifests[templateName].Selector)
+				resultString += fmt.Sprintf("\tSelected: %s\n", selection)
+				return resultString
+			}
+			return funcmap
+		}(),
+	}
+}
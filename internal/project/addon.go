@@ -5,23 +5,35 @@ import (
 )
 
 type AddonHandler interface {
-	// IsAddonEnabled checks if the addon is enabled
+	// IsAddonEnabled checks if the addon has been enabled explicitly
 	IsAddonEnabled(name string) bool
 	// EnableAddon enables the addon
 	EnableAddon(name string)
 	// DisableAddon disables the addon
 	DisableAddon(name string)
+	// InheritAddon resets the enablement of the addon to the one of the parent
+	InheritAddon(name string)
 	// GetAddons returns the addons
 	GetAddons() ClusterAddons
 	// GetAddon returns the addon by name
 	GetAddon(name string) *ClusterAddon
 }
 
+// AddonState is the enablement of an addon at a single level
+type AddonState string
+
+const (
+	// AddonStateInherit means that the enablement is inherited from the parent
+	AddonStateInherit  AddonState = "inherit"
+	AddonStateEnabled  AddonState = "enabled"
+	AddonStateDisabled AddonState = "disabled"
+)
+
 type ClusterAddons map[string]*ClusterAddon
 
 func (ca ClusterAddons) AllRequiredPropertiesSet(config *ProjectConfig, skipOnFailure bool) error {
 	for addonName, addon := range ca {
-		if !addon.Enabled {
+		if addon.State() == AddonStateDisabled {
 			fmt.Printf("addon %s is disabled\n", addonName)
 			continue
 		}
@@ -41,7 +53,8 @@ func (ca ClusterAddons) IsEnabled(addon string) bool {
 }
 
 type ClusterAddon struct {
-	Enabled    bool           `json:"enabled"`
+	// Enabled is the enablement of the addon, if not set the enablement is inherited from the parent
+	Enabled    *bool          `json:"enabled,omitempty"`
 	Properties map[string]any `json:"properties"`
 }
 
@@ -59,9 +72,20 @@ func (ca *ClusterAddon) AllRequiredPropertiesSet(config *ProjectConfig, addonNam
 	return nil
 }
 
-// IsEnabled checks if the addon is enabled
+// IsEnabled checks if the addon has been enabled explicitly
 func (ca ClusterAddon) IsEnabled() bool {
-	return ca.Enabled
+	return ca.Enabled != nil && *ca.Enabled
+}
+
+// State returns the enablement state of the addon, a missing addon inherits the enablement
+func (ca *ClusterAddon) State() AddonState {
+	if ca == nil || ca.Enabled == nil {
+		return AddonStateInherit
+	}
+	if *ca.Enabled {
+		return AddonStateEnabled
+	}
+	return AddonStateDisabled
 }
 
 func (ca *ClusterAddon) SetProperty(key string, value any) {
@@ -70,3 +94,28 @@ func (ca *ClusterAddon) SetProperty(key string, value any) {
 	}
 	ca.Properties[key] = value
 }
+
+// addonLevel is the addon state of an environment, stage or cluster
+type addonLevel struct {
+	origin PropertyOrigin
+	addon  *ClusterAddon
+}
+
+// resolveAddonState returns if the addon is enabled and the level that decided it, the last explicit state wins
+// If no level sets an explicit state, the default applies
+func resolveAddonState(defaultEnabled bool, levels ...addonLevel) (bool, PropertyOrigin) {
+	enabled, origin := defaultEnabled, PropertyOriginDefault
+	for _, level := range levels {
+		switch level.addon.State() {
+		case AddonStateEnabled:
+			enabled, origin = true, level.origin
+		case AddonStateDisabled:
+			enabled, origin = false, level.origin
+		}
+	}
+	return enabled, origin
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
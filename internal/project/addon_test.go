@@ -49,7 +49,7 @@ func TestClusterAddons_AllRequiredPropertiesSet(t *testing.T) {
 			fields: fields{
 				Addons: ClusterAddons{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 						Properties: map[string]any{
 							"property1": true,
 						},
@@ -80,7 +80,7 @@ func TestClusterAddons_AllRequiredPropertiesSet(t *testing.T) {
 			fields: fields{
 				Addons: ClusterAddons{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 						Properties: map[string]any{
 							"property1": "invalid",
 						},
@@ -111,7 +111,7 @@ func TestClusterAddons_AllRequiredPropertiesSet(t *testing.T) {
 			fields: fields{
 				Addons: ClusterAddons{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 						Properties: map[string]any{
 							"property1": 10,
 						},
@@ -142,7 +142,7 @@ func TestClusterAddons_AllRequiredPropertiesSet(t *testing.T) {
 			fields: fields{
 				Addons: ClusterAddons{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 						Properties: map[string]any{
 							"property1": true,
 						},
@@ -173,7 +173,7 @@ func TestClusterAddons_AllRequiredPropertiesSet(t *testing.T) {
 			fields: fields{
 				Addons: ClusterAddons{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 						Properties: map[string]any{
 							"property1": 10,
 						},
@@ -204,7 +204,7 @@ func TestClusterAddons_AllRequiredPropertiesSet(t *testing.T) {
 			fields: fields{
 				Addons: ClusterAddons{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 						Properties: map[string]any{
 							"property1": "value",
 						},
@@ -235,7 +235,7 @@ func TestClusterAddons_AllRequiredPropertiesSet(t *testing.T) {
 			fields: fields{
 				Addons: ClusterAddons{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 						Properties: map[string]any{
 							"property1": 10,
 						},
@@ -266,7 +266,7 @@ func TestClusterAddons_AllRequiredPropertiesSet(t *testing.T) {
 			fields: fields{
 				Addons: ClusterAddons{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 						Properties: map[string]any{
 							"property1": "value",
 						},
@@ -297,7 +297,7 @@ func TestClusterAddons_AllRequiredPropertiesSet(t *testing.T) {
 			fields: fields{
 				Addons: ClusterAddons{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 						Properties: map[string]any{
 							"property1": 10,
 						},
@@ -328,7 +328,7 @@ func TestClusterAddons_AllRequiredPropertiesSet(t *testing.T) {
 			fields: fields{
 				Addons: ClusterAddons{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 						Properties: map[string]any{
 							"property1": "invalid",
 						},
@@ -359,7 +359,7 @@ func TestClusterAddons_AllRequiredPropertiesSet(t *testing.T) {
 			fields: fields{
 				Addons: ClusterAddons{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 						Properties: map[string]any{
 							"property1": "invalid",
 						},
@@ -398,7 +398,7 @@ func TestClusterAddons_AllRequiredPropertiesSet(t *testing.T) {
 
 func TestClusterAddon_AllRequiredPropertiesSet(t *testing.T) {
 	type fields struct {
-		Enabled    bool
+		Enabled    *bool
 		Properties map[string]any
 	}
 	type args struct {
@@ -749,7 +749,7 @@ func TestClusterAddons_IsEnabled(t *testing.T) {
 			name: "addon not enabled",
 			ca: ClusterAddons{
 				"addon1": {
-					Enabled: false,
+					Enabled: boolPtr(false),
 				},
 			},
 			args: args{
@@ -761,7 +761,7 @@ func TestClusterAddons_IsEnabled(t *testing.T) {
 			name: "addon enabled",
 			ca: ClusterAddons{
 				"addon1": {
-					Enabled: true,
+					Enabled: boolPtr(true),
 				},
 			},
 			args: args{
@@ -781,7 +781,7 @@ func TestClusterAddons_IsEnabled(t *testing.T) {
 
 func TestClusterAddon_IsEnabled(t *testing.T) {
 	type fields struct {
-		Enabled    bool
+		Enabled    *bool
 		Properties map[string]any
 	}
 	tests := []struct {
@@ -792,14 +792,14 @@ func TestClusterAddon_IsEnabled(t *testing.T) {
 		{
 			name: "addon not enabled",
 			fields: fields{
-				Enabled: false,
+				Enabled: boolPtr(false),
 			},
 			want: false,
 		},
 		{
 			name: "addon enabled",
 			fields: fields{
-				Enabled: true,
+				Enabled: boolPtr(true),
 			},
 			want: true,
 		},
@@ -819,7 +819,7 @@ func TestClusterAddon_IsEnabled(t *testing.T) {
 
 func TestClusterAddon_SetProperty(t *testing.T) {
 	type fields struct {
-		Enabled    bool
+		Enabled    *bool
 		Properties map[string]any
 	}
 	type args struct {
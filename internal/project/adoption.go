@@ -0,0 +1,72 @@
+package project
+
+import (
+	"reflect"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
+)
+
+// AdoptionMatrix contains the addon enablement of every cluster, the cells of a row are in the order of the addons
+type AdoptionMatrix struct {
+	Addons []string      `json:"addons"`
+	Rows   []AdoptionRow `json:"rows"`
+}
+
+// AdoptionRow is the addon enablement of a single cluster
+type AdoptionRow struct {
+	Environment string         `json:"environment"`
+	Stage       string         `json:"stage"`
+	Cluster     string         `json:"cluster"`
+	Cells       []AdoptionCell `json:"cells"`
+}
+
+// AdoptionCell is the enablement of an addon for a cluster together with the level that decided it
+type AdoptionCell struct {
+	Addon   string         `json:"addon"`
+	Enabled bool           `json:"enabled"`
+	Origin  PropertyOrigin `json:"origin"`
+	// Properties contains the properties of an enabled addon that do not use the default value, secrets are masked
+	Properties []ExplainedProperty `json:"properties,omitempty"`
+}
+
+// AddonEnablement returns if the addon is enabled for the cluster and the level that decided it
+// The origin is the most specific level with an explicit state, or default if the addon default applies
+func (c *Cluster) AddonEnablement(config *ProjectConfig, addon, env, stage string) (bool, PropertyOrigin) {
+	return resolveAddonState(
+		config.Addons[addon].DefaultEnabled,
+		addonLevel{PropertyOriginEnvironment, config.GetEnvironment(env).GetAddon(addon)},
+		addonLevel{PropertyOriginStage, config.GetStage(env, stage).GetAddon(addon)},
+		addonLevel{PropertyOriginCluster, c.GetAddon(addon)},
+	)
+}
+
+// AddonAdoption returns the enablement of all addons of the project for the clusters selected by the filter
+func (p *ProjectConfig) AddonAdoption(filter InventoryFilter) AdoptionMatrix {
+	matrix := AdoptionMatrix{
+		Addons: utils.SortStringSlice(utils.MapKeysToList(p.Addons)),
+		Rows:   []AdoptionRow{},
+	}
+	for _, info := range p.ListClusters(filter) {
+		cluster := p.GetCluster(info.Environment, info.Stage, info.Name)
+		if cluster.Name == "" {
+			cluster.Name = info.Name
+		}
+		row := AdoptionRow{Environment: info.Environment, Stage: info.Stage, Cluster: info.Name, Cells: []AdoptionCell{}}
+		for _, addon := range matrix.Addons {
+			cell := AdoptionCell{Addon: addon}
+			cell.Enabled, cell.Origin = cluster.AddonEnablement(p, addon, info.Environment, info.Stage)
+			if cell.Enabled {
+				for _, ep := range cluster.ExplainAddonProperties(p, addon, info.Environment, info.Stage) {
+					// values that have been set explicitly, but equal the default are not relevant either
+					if ep.Origin == PropertyOriginDefault || ep.Value == nil || reflect.DeepEqual(ep.Value, p.ParsedAddons[addon].Properties[ep.Key].Default) {
+						continue
+					}
+					cell.Properties = append(cell.Properties, ep)
+				}
+			}
+			row.Cells = append(row.Cells, cell)
+		}
+		matrix.Rows = append(matrix.Rows, row)
+	}
+	return matrix
+}
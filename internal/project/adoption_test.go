@@ -0,0 +1,100 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+)
+
+func TestCluster_AddonEnablement(t *testing.T) {
+	config := newInventoryConfig()
+	tests := []struct {
+		name        string
+		addon       string
+		env         string
+		stage       string
+		cluster     string
+		wantEnabled bool
+		wantOrigin  PropertyOrigin
+	}{
+		{
+			name:        "addon default",
+			addon:       "monitoring",
+			env:         "dev",
+			stage:       "dev",
+			cluster:     "hugi",
+			wantEnabled: true,
+			wantOrigin:  PropertyOriginDefault,
+		},
+		{
+			name:        "stage",
+			addon:       "debug",
+			env:         "dev",
+			stage:       "dev",
+			cluster:     "hugi",
+			wantEnabled: true,
+			wantOrigin:  PropertyOriginStage,
+		},
+		{
+			name:        "cluster",
+			addon:       "monitoring",
+			env:         "dev",
+			stage:       "dev",
+			cluster:     "munin",
+			wantEnabled: false,
+			wantOrigin:  PropertyOriginCluster,
+		},
+		{
+			name:        "disabled by default",
+			addon:       "debug",
+			env:         "prod",
+			stage:       "prod",
+			cluster:     "odin",
+			wantEnabled: false,
+			wantOrigin:  PropertyOriginDefault,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := config.GetCluster(tt.env, tt.stage, tt.cluster)
+			enabled, origin := c.AddonEnablement(config, tt.addon, tt.env, tt.stage)
+			if enabled != tt.wantEnabled || origin != tt.wantOrigin {
+				t.Errorf("Cluster.AddonEnablement() = %v, %v, want %v, %v", enabled, origin, tt.wantEnabled, tt.wantOrigin)
+			}
+		})
+	}
+}
+
+func TestProjectConfig_AddonAdoption(t *testing.T) {
+	config := newInventoryConfig()
+	config.ParsedAddons = map[string]template.TemplateManifest{
+		"monitoring": {Name: "monitoring", Properties: map[string]template.Property{
+			"retention": {Type: template.PropertyTypeString, Default: "7d"},
+			"replicas":  {Type: template.PropertyTypeInt, Default: 1},
+		}},
+		"debug": {Name: "debug"},
+	}
+	config.Environments["prod"].Addons = map[string]*ClusterAddon{
+		"monitoring": {Properties: map[string]any{"retention": "30d", "replicas": 1}},
+	}
+
+	want := AdoptionMatrix{
+		Addons: []string{"debug", "monitoring"},
+		Rows: []AdoptionRow{
+			{
+				Environment: "prod", Stage: "prod", Cluster: "odin",
+				Cells: []AdoptionCell{
+					{Addon: "debug", Enabled: false, Origin: PropertyOriginDefault},
+					{Addon: "monitoring", Enabled: true, Origin: PropertyOriginDefault, Properties: []ExplainedProperty{
+						{Key: "retention", PropertyValue: PropertyValue{Value: "30d", Origin: PropertyOriginEnvironment}, Shadowed: []PropertyValue{{Value: "7d", Origin: PropertyOriginDefault}}},
+					}},
+				},
+			},
+		},
+	}
+	got := config.AddonAdoption(InventoryFilter{Environment: "prod"})
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ProjectConfig.AddonAdoption() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -3,48 +3,55 @@ package project
 import (
 	"fmt"
 	"path"
+	"slices"
 
 	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
 	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
 )
 
 var (
-	_ AddonHandler = &Cluster{}
+	_ AddonHandler    = &Cluster{}
+	_ TemplateHandler = &Cluster{}
 )
 
 type Cluster struct {
 	Name       string                   `json:"-"`
+	Labels     map[string]string        `json:"labels,omitempty"`
 	Addons     map[string]*ClusterAddon `json:"addons"`
-	Properties map[string]string        `json:"properties"`
+	Properties map[string]any           `json:"properties"`
+	// Templates is the list of base templates that are rendered for the cluster
+	// If nil, the templates of the stage are used
+	Templates []string `json:"templates"`
 }
 
-// IsAddonEnabled checks if the addon is enabled for the cluster
+// IsAddonEnabled checks if the addon has been enabled explicitly for the cluster
 func (c Cluster) IsAddonEnabled(addon string) bool {
-	_, ok := c.Addons[addon]
-	if !ok {
-		return false
-	}
-	return c.Addons[addon].Enabled
+	return c.Addons[addon].State() == AddonStateEnabled
 }
 
 // EnableAddon enables the addon for the cluster by setting the enabled flag to true
 func (c *Cluster) EnableAddon(addon string) {
 	if c.Addons[addon] == nil {
-		c.Addons[addon] = &ClusterAddon{
-			Enabled: true,
-		}
-		return
+		c.Addons[addon] = &ClusterAddon{}
 	}
-	c.Addons[addon].Enabled = true
+	c.Addons[addon].Enabled = boolPtr(true)
 }
 
 // DisableAddon disables the addon for the cluster by setting the enabled flag to false
 func (c *Cluster) DisableAddon(addon string) {
-	if _, ok := c.Addons[addon]; !ok {
-		// already disabled or not found
+	if c.Addons[addon] == nil {
+		c.Addons[addon] = &ClusterAddon{}
+	}
+	c.Addons[addon].Enabled = boolPtr(false)
+}
+
+// InheritAddon resets the enabled flag of the addon, so that the enablement is inherited from the parent
+func (c *Cluster) InheritAddon(addon string) {
+	if c.Addons[addon] == nil {
+		// already inherited
 		return
 	}
-	c.Addons[addon].Enabled = false
+	c.Addons[addon].Enabled = nil
 }
 
 // GetAddons returns the cluster addons
@@ -57,63 +64,131 @@ func (c *Cluster) GetAddon(name string) *ClusterAddon {
 	return c.Addons[name]
 }
 
-// Render renders the cluster configuration using the given project templates
-func (c *Cluster) Render(config *ProjectConfig, env, stage string) error {
-	properties := utils.MergeMaps(config.EnvStageProperty(env, stage), c.Properties)
+// GetTemplates returns the base templates selected for the cluster
+func (c *Cluster) GetTemplates() []string {
+	return c.Templates
+}
+
+// SetTemplates sets the base templates selected for the cluster
+func (c *Cluster) SetTemplates(templates []string) {
+	c.Templates = templates
+}
+
+// SelectedTemplates returns the names of the base templates that have been selected for the cluster
+// If the cluster does not select templates, the selection of the stage and then the environment is used
+// If no level selects templates, nil is returned
+func (c *Cluster) SelectedTemplates(config *ProjectConfig, env, stage string) []string {
+	if c.Templates != nil {
+		return c.Templates
+	}
+	if templates := config.GetStage(env, stage).Templates; templates != nil {
+		return templates
+	}
+	return config.GetEnvironment(env).Templates
+}
+
+// IsTemplateEnabled checks if the base template must be rendered for the cluster
+// If templates have been selected, only the selected templates are rendered, otherwise the template selector must match the cluster labels
+func (c *Cluster) IsTemplateEnabled(config *ProjectConfig, env, stage string, tm template.TemplateManifest) bool {
+	if selected := c.SelectedTemplates(config, env, stage); selected != nil {
+		return slices.Contains(selected, tm.Name)
+	}
+	return tm.MatchesLabels(c.Labels)
+}
+
+// Render renders the cluster configuration using the given project templates and returns the rendered files
+func (c *Cluster) Render(config *ProjectConfig, env, stage string) ([]template.RenderedFile, error) {
+	violations, err := config.CheckClusterPolicies(env, stage, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check policies: %w", err)
+	}
+	err = PolicyError(violations)
+	if err != nil {
+		return nil, err
+	}
+
+	properties, err := c.ResolvedProperties(config, env, stage)
+	if err != nil {
+		return nil, err
+	}
 
 	templates, err := template.LoadTemplateManifest(config.TemplateBasePath)
 	if err != nil {
-		return fmt.Errorf("failed to load base templates: %w", err)
+		return nil, fmt.Errorf("failed to load base templates: %w", err)
 	}
 
-	addonProperties := c.AddonProperties(config, env, stage)
-	addons := map[string]template.AddonData{}
-	for k, v := range addonProperties {
-		addons[k] = template.AddonData{
-			Enabled:     v.Enabled,
-			Group:       config.ParsedAddons[k].Group,
-			Annotations: config.ParsedAddons[k].Annotations,
-			Properties:  v.Properties,
-		}
+	helpers, err := template.LoadHelpers(config.HelpersPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load helpers: %w", err)
+	}
+
+	addons, err := c.addonData(config, env, stage, properties)
+	if err != nil {
+		return nil, err
+	}
+	clusters := config.clusterSource()
+	sealingKey, err := config.SealingKey(env)
+	if err != nil {
+		return nil, err
 	}
 
+	rendered := []template.RenderedFile{}
+
 	// render templates
 	for _, t := range templates {
-		err = t.Render(config.BasePath, template.TemplateData{
-			BasePath:    config.BasePath,
-			ClusterPath: path.Join(config.BasePath, env, stage, c.Name),
-			Environment: env,
-			Stage:       stage,
-			ClusterName: c.Name,
-			Properties:  properties,
-			Addons:      addons,
+		if t.TemplateManifest.GetScope() != template.TemplateScopeCluster {
+			// environment and stage templates are rendered by the environment and stage
+			continue
+		}
+		if !c.IsTemplateEnabled(config, env, stage, t.TemplateManifest) {
+			// template was not selected for the cluster
+			continue
+		}
+		files, err := t.Render(config.BasePath, helpers, template.TemplateData{
+			BasePath:      config.BasePath,
+			ClusterPath:   path.Join(config.BasePath, env, stage, c.Name),
+			Environment:   env,
+			Stage:         stage,
+			ClusterName:   c.Name,
+			Properties:    properties,
+			Addons:        addons,
+			ClusterSource: clusters,
+			SealingKey:    sealingKey,
 		})
 		if err != nil {
-			return fmt.Errorf("failed to render template: %w", err)
+			return nil, fmt.Errorf("failed to render template: %w", err)
 		}
+		rendered = append(rendered, files...)
 	}
 
 	// render addons
 	for addonName, addonValue := range addons {
-		atc, err := template.LoadTemplatesFromAddonManifest(config.ParsedAddons[addonName])
+		if !addonValue.Enabled {
+			continue
+		}
+		atc, err := template.LoadTemplatesFromAddonManifest(config.ParsedAddons[addonName], helpers, env, stage)
 		if err != nil {
-			return fmt.Errorf("failed to load addon %s templates: %w, value: %+v", addonName, err, config.ParsedAddons[addonName])
+			return nil, fmt.Errorf("failed to load addon %s templates: %w, value: %+v", addonName, err, config.ParsedAddons[addonName])
 		}
-		err = atc.Render(config.BasePath, template.AddonTemplateData{
+		files, err := atc.Render(config.BasePath, template.AddonTemplateData{
 			Environment:       env,
 			Stage:             stage,
 			Cluster:           c.Name,
 			ClusterProperties: properties,
 			Properties:        addonValue.Properties,
+			ClusterSource:     clusters,
+			SealingKey:        sealingKey,
 		})
 		if err != nil {
-			return fmt.Errorf("failed to render addon: %s, Error: %w", addonName, err)
+			return nil, fmt.Errorf("failed to render addon: %s, Error: %w", addonName, err)
 		}
+		rendered = append(rendered, files...)
 	}
-	return nil
+	return rendered, nil
 }
 
-// SetDefaultAddons sets the default addons for the cluster
+// SetDefaultAddons adds the default enabled addons with their default properties to the cluster
+// The enablement of the added addons is inherited from the stage
 func (c *Cluster) SetDefaultAddons(config *ProjectConfig) {
 	for addonName, addon := range config.Addons {
 		if !addon.DefaultEnabled {
@@ -128,38 +203,173 @@ func (c *Cluster) SetDefaultAddons(config *ProjectConfig) {
 		}
 
 		cAddon := &ClusterAddon{
-			Enabled:    true,
 			Properties: map[string]any{},
 		}
 
 		for key, property := range config.ParsedAddons[addonName].Properties {
+			if property.HasTemplatedDefault() {
+				// templated defaults are rendered for the cluster at render time
+				continue
+			}
 			cAddon.Properties[key] = property.Default
 		}
 		c.Addons[addonName] = cAddon
 	}
 }
 
-// AddonProperties returns the addon properties for the cluster merged with the environment and stage properties
-func (c *Cluster) AddonProperties(config *ProjectConfig, env, stg string) map[string]*ClusterAddon {
-	properties := c.Addons
-	for addonName, addon := range c.Addons {
-		if !addon.Enabled {
-			// addon was disabled on the cluster level, we skip it
-			continue
+// ResolvedProperties returns the merged cluster properties with all secrets decrypted and references resolved
+func (c *Cluster) ResolvedProperties(config *ProjectConfig, env, stage string) (map[string]any, error) {
+	properties, err := config.decryptSecrets(config.PropertyDefinitions(), c.PropertyLayers(config, env, stage).Merge(config.PropertyDefinitions()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt properties of cluster %s: %w", c.Name, err)
+	}
+	properties, err = interpolateProperties(interpolationScope{
+		Environment: env,
+		Stage:       stage,
+		Cluster:     c.Name,
+	}, properties)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve properties of cluster %s: %w", c.Name, err)
+	}
+	return properties, nil
+}
+
+// maskedProperties returns the merged cluster properties with all secrets masked and references resolved
+// The secrets are masked before the references are resolved, so they cannot leak through other properties
+func (c *Cluster) maskedProperties(config *ProjectConfig, env, stage string) (map[string]any, error) {
+	properties := maskSecretValues(config.PropertyDefinitions(), c.PropertyLayers(config, env, stage).Merge(config.PropertyDefinitions()))
+	properties, err := interpolateProperties(interpolationScope{
+		Environment: env,
+		Stage:       stage,
+		Cluster:     c.Name,
+	}, properties)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve properties of cluster %s: %w", c.Name, err)
+	}
+	return properties, nil
+}
+
+// addonData returns the addon data of the cluster as it is passed to the templates
+// The references in the properties of enabled addons are resolved against the given cluster properties
+func (c *Cluster) addonData(config *ProjectConfig, env, stage string, properties map[string]any) (map[string]template.AddonData, error) {
+	addons := map[string]template.AddonData{}
+	for k, v := range c.AddonProperties(config, env, stage) {
+		addonProperties := v.Properties
+		if *v.Enabled {
+			var err error
+			addonProperties, err = c.resolvedAddonProperties(config, k, env, stage, properties)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve properties of addon %s: %w", k, err)
+			}
 		}
-		envAddonProps := map[string]any{}
-		if env := config.GetEnvironment(env).GetAddon(addonName); env != nil {
-			envAddonProps = env.Properties
+		addons[k] = template.AddonData{
+			Enabled:     *v.Enabled,
+			Group:       config.ParsedAddons[k].Group,
+			Annotations: config.ParsedAddons[k].Annotations,
+			Properties:  addonProperties,
 		}
-		stageAddonProps := map[string]any{}
-		if stg := config.GetStage(env, stg).GetAddon(addonName); stg != nil {
-			stageAddonProps = stg.Properties
+	}
+	return addons, nil
+}
+
+// resolvedAddonProperties merges the addon properties with the rendered defaults, decrypts the secrets and resolves all references against the given cluster properties
+func (c *Cluster) resolvedAddonProperties(config *ProjectConfig, addon, env, stage string, properties map[string]any) (map[string]any, error) {
+	defaults, err := c.AddonDefaults(config, addon, env, stage, properties)
+	if err != nil {
+		return nil, err
+	}
+	layers := c.addonPropertyLayers(config, addon, env, stage)
+	// the first layer always contains the addon defaults
+	layers[0].Properties = defaults
+	values, err := config.decryptSecrets(config.ParsedAddons[addon].Properties, layers.Merge(config.ParsedAddons[addon].Properties))
+	if err != nil {
+		return nil, err
+	}
+	return interpolateValues(interpolationScope{
+		Environment: env,
+		Stage:       stage,
+		Cluster:     c.Name,
+	}, properties, values)
+}
+
+// AddonDefaults returns the default values of the addon properties for the cluster
+// Templated defaults are rendered with the cluster name, environment, stage and the given cluster properties
+func (c *Cluster) AddonDefaults(config *ProjectConfig, addon, env, stage string, properties map[string]any) (map[string]any, error) {
+	sealingKey, err := config.SealingKey(env)
+	if err != nil {
+		return nil, err
+	}
+	return template.RenderDefaults(config.ParsedAddons[addon].Properties, template.TemplateData{
+		BasePath:    config.BasePath,
+		ClusterPath: path.Join(config.BasePath, env, stage, c.Name),
+		Environment: env,
+		Stage:       stage,
+		ClusterName: c.Name,
+		Properties:  properties,
+		SealingKey:  sealingKey,
+	})
+}
+
+// AddonEnabled checks if the addon is enabled for the cluster
+// The enablement is inherited along the addon default, environment, stage and cluster, the most specific explicit state wins
+func (c *Cluster) AddonEnabled(config *ProjectConfig, addon, env, stage string) bool {
+	enabled, _ := c.AddonEnablement(config, addon, env, stage)
+	return enabled
+}
+
+// AddonProperties returns the resolved enablement and the addon properties for the cluster merged with the environment and stage properties
+// The result contains all addons of the project and the cluster, the addons of the cluster are not modified
+func (c *Cluster) AddonProperties(config *ProjectConfig, env, stg string) map[string]*ClusterAddon {
+	addonNames := utils.MapKeysToList(c.Addons)
+	for addonName := range config.ParsedAddons {
+		if _, ok := c.Addons[addonName]; !ok {
+			addonNames = append(addonNames, addonName)
 		}
-		addonProps := map[string]any{}
-		for key, property := range config.ParsedAddons[addonName].Properties {
-			addonProps[key] = property.Default
+	}
+
+	properties := map[string]*ClusterAddon{}
+	for _, addonName := range addonNames {
+		enabled := c.AddonEnabled(config, addonName, env, stg)
+		if !enabled {
+			// disabled addons are not merged
+			var addonProperties map[string]any
+			if addon := c.GetAddon(addonName); addon != nil {
+				addonProperties = addon.Properties
+			}
+			properties[addonName] = &ClusterAddon{
+				Enabled:    boolPtr(false),
+				Properties: addonProperties,
+			}
+			continue
+		}
+		properties[addonName] = &ClusterAddon{
+			Enabled:    boolPtr(true),
+			Properties: c.addonPropertyLayers(config, addonName, env, stg).Merge(config.ParsedAddons[addonName].Properties),
 		}
-		properties[addonName].Properties = utils.MergeMaps(addonProps, envAddonProps, stageAddonProps, c.GetAddon(addonName).Properties)
 	}
 	return properties
 }
+
+// addonPropertyLayers returns the property layers of the addon including the addon settings of the cluster
+func (c *Cluster) addonPropertyLayers(config *ProjectConfig, addon, env, stage string) PropertyLayers {
+	layers := config.AddonPropertyLayersAt(addon, env, stage)
+	if ca := c.GetAddon(addon); ca != nil {
+		layers = append(layers, PropertyLayer{Origin: PropertyOriginCluster, Properties: ca.Properties})
+	}
+	return layers
+}
+
+// PropertyLayers returns the property layers of the cluster, from the schema defaults to the cluster properties
+func (c *Cluster) PropertyLayers(config *ProjectConfig, env, stage string) PropertyLayers {
+	return append(config.PropertyLayersAt(env, stage), PropertyLayer{Origin: PropertyOriginCluster, Properties: c.Properties})
+}
+
+// ExplainProperties returns the effective cluster properties together with their origin
+func (c *Cluster) ExplainProperties(config *ProjectConfig, env, stage string) []ExplainedProperty {
+	return c.PropertyLayers(config, env, stage).Explain(config.PropertyDefinitions())
+}
+
+// ExplainAddonProperties returns the effective properties of the addon together with their origin
+func (c *Cluster) ExplainAddonProperties(config *ProjectConfig, addon, env, stage string) []ExplainedProperty {
+	return c.addonPropertyLayers(config, addon, env, stage).Explain(config.ParsedAddons[addon].Properties)
+}
@@ -12,7 +12,7 @@ func TestCluster_IsAddonEnabled(t *testing.T) {
 	type fields struct {
 		Name       string
 		Addons     map[string]*ClusterAddon
-		Properties map[string]string
+		Properties map[string]any
 	}
 	type args struct {
 		addon string
@@ -28,7 +28,7 @@ func TestCluster_IsAddonEnabled(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -42,7 +42,7 @@ func TestCluster_IsAddonEnabled(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -66,10 +66,10 @@ func TestCluster_IsAddonEnabled(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -83,10 +83,10 @@ func TestCluster_IsAddonEnabled(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -100,10 +100,10 @@ func TestCluster_IsAddonEnabled(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 					"addon2": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -117,10 +117,10 @@ func TestCluster_IsAddonEnabled(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -148,7 +148,7 @@ func TestCluster_EnableAddon(t *testing.T) {
 	type fields struct {
 		Name       string
 		Addons     map[string]*ClusterAddon
-		Properties map[string]string
+		Properties map[string]any
 	}
 	type args struct {
 		addon string
@@ -170,7 +170,7 @@ func TestCluster_EnableAddon(t *testing.T) {
 			want: Cluster{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -180,7 +180,7 @@ func TestCluster_EnableAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -190,7 +190,7 @@ func TestCluster_EnableAddon(t *testing.T) {
 			want: Cluster{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -200,7 +200,7 @@ func TestCluster_EnableAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -210,7 +210,7 @@ func TestCluster_EnableAddon(t *testing.T) {
 			want: Cluster{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -220,10 +220,10 @@ func TestCluster_EnableAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -233,10 +233,10 @@ func TestCluster_EnableAddon(t *testing.T) {
 			want: Cluster{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -246,10 +246,10 @@ func TestCluster_EnableAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 					"addon2": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -259,10 +259,10 @@ func TestCluster_EnableAddon(t *testing.T) {
 			want: Cluster{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -290,7 +290,7 @@ func TestCluster_DisableAddon(t *testing.T) {
 	type fields struct {
 		Name       string
 		Addons     map[string]*ClusterAddon
-		Properties map[string]string
+		Properties map[string]any
 	}
 	type args struct {
 		addon string
@@ -310,7 +310,9 @@ func TestCluster_DisableAddon(t *testing.T) {
 				addon: "addon1",
 			},
 			want: Cluster{
-				Addons: map[string]*ClusterAddon{},
+				Addons: map[string]*ClusterAddon{
+					"addon1": {Enabled: boolPtr(false)},
+				},
 			},
 		},
 		{
@@ -318,7 +320,7 @@ func TestCluster_DisableAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled:    true,
+						Enabled:    boolPtr(true),
 						Properties: map[string]any{},
 					},
 				},
@@ -329,7 +331,7 @@ func TestCluster_DisableAddon(t *testing.T) {
 			want: Cluster{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled:    false,
+						Enabled:    boolPtr(false),
 						Properties: map[string]any{},
 					},
 				},
@@ -340,7 +342,7 @@ func TestCluster_DisableAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -350,7 +352,7 @@ func TestCluster_DisableAddon(t *testing.T) {
 			want: Cluster{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled:    false,
+						Enabled:    boolPtr(false),
 						Properties: nil,
 					},
 				},
@@ -379,7 +381,7 @@ func TestCluster_GetAddons(t *testing.T) {
 	type fields struct {
 		Name       string
 		Addons     map[string]*ClusterAddon
-		Properties map[string]string
+		Properties map[string]any
 	}
 	tests := []struct {
 		name   string
@@ -398,13 +400,13 @@ func TestCluster_GetAddons(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
 			want: map[string]*ClusterAddon{
 				"addon1": {
-					Enabled: true,
+					Enabled: boolPtr(true),
 				},
 			},
 		},
@@ -413,19 +415,19 @@ func TestCluster_GetAddons(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
 			want: map[string]*ClusterAddon{
 				"addon1": {
-					Enabled: true,
+					Enabled: boolPtr(true),
 				},
 				"addon2": {
-					Enabled: false,
+					Enabled: boolPtr(false),
 				},
 			},
 		},
@@ -448,7 +450,7 @@ func TestCluster_GetAddon(t *testing.T) {
 	type fields struct {
 		Name       string
 		Addons     map[string]*ClusterAddon
-		Properties map[string]string
+		Properties map[string]any
 	}
 	type args struct {
 		name string
@@ -474,7 +476,7 @@ func TestCluster_GetAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -482,7 +484,7 @@ func TestCluster_GetAddon(t *testing.T) {
 				name: "addon1",
 			},
 			want: &ClusterAddon{
-				Enabled: true,
+				Enabled: boolPtr(true),
 			},
 		},
 		{
@@ -490,10 +492,10 @@ func TestCluster_GetAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -501,7 +503,7 @@ func TestCluster_GetAddon(t *testing.T) {
 				name: "addon1",
 			},
 			want: &ClusterAddon{
-				Enabled: true,
+				Enabled: boolPtr(true),
 			},
 		},
 		{
@@ -509,10 +511,10 @@ func TestCluster_GetAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -540,7 +542,7 @@ func TestCluster_Render(t *testing.T) {
 	type fields struct {
 		Name       string
 		Addons     map[string]*ClusterAddon
-		Properties map[string]string
+		Properties map[string]any
 	}
 	type args struct {
 		config *ProjectConfig
@@ -562,7 +564,7 @@ func TestCluster_Render(t *testing.T) {
 				Addons:     tt.fields.Addons,
 				Properties: tt.fields.Properties,
 			}
-			if err := c.Render(tt.args.config, tt.args.env, tt.args.stage); (err != nil) != tt.wantErr {
+			if _, err := c.Render(tt.args.config, tt.args.env, tt.args.stage); (err != nil) != tt.wantErr {
 				t.Errorf("Cluster.Render() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -573,7 +575,7 @@ func TestCluster_SetDefaultAddons(t *testing.T) {
 	type fields struct {
 		Name       string
 		Addons     map[string]*ClusterAddon
-		Properties map[string]string
+		Properties map[string]any
 	}
 	type args struct {
 		config *ProjectConfig
@@ -613,7 +615,6 @@ func TestCluster_SetDefaultAddons(t *testing.T) {
 			},
 			wantAddons: map[string]*ClusterAddon{
 				"addon1": {
-					Enabled:    true,
 					Properties: map[string]any{"property1": false},
 				},
 			},
@@ -647,7 +648,6 @@ func TestCluster_SetDefaultAddons(t *testing.T) {
 			},
 			wantAddons: map[string]*ClusterAddon{
 				"addon1": {
-					Enabled:    true,
 					Properties: map[string]any{"property1": nil},
 				},
 			},
@@ -681,7 +681,6 @@ func TestCluster_SetDefaultAddons(t *testing.T) {
 			},
 			wantAddons: map[string]*ClusterAddon{
 				"addon1": {
-					Enabled:    true,
 					Properties: map[string]any{"property1": 10},
 				},
 			},
@@ -715,7 +714,6 @@ func TestCluster_SetDefaultAddons(t *testing.T) {
 			},
 			wantAddons: map[string]*ClusterAddon{
 				"addon1": {
-					Enabled:    true,
 					Properties: map[string]any{"property1": nil},
 				},
 			},
@@ -749,7 +747,6 @@ func TestCluster_SetDefaultAddons(t *testing.T) {
 			},
 			wantAddons: map[string]*ClusterAddon{
 				"addon1": {
-					Enabled:    true,
 					Properties: map[string]any{"property1": "Hello World"},
 				},
 			},
@@ -783,7 +780,6 @@ func TestCluster_SetDefaultAddons(t *testing.T) {
 			},
 			wantAddons: map[string]*ClusterAddon{
 				"addon1": {
-					Enabled:    true,
 					Properties: map[string]any{"property1": nil},
 				},
 			},
@@ -811,7 +807,7 @@ func TestCluster_AddonProperties(t *testing.T) {
 	type fields struct {
 		Name       string
 		Addons     map[string]*ClusterAddon
-		Properties map[string]string
+		Properties map[string]any
 	}
 	type args struct {
 		config *ProjectConfig
@@ -830,7 +826,7 @@ func TestCluster_AddonProperties(t *testing.T) {
 				Name: "cluster1",
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 						Properties: map[string]any{
 							"property1": "value1",
 						},
@@ -863,7 +859,7 @@ func TestCluster_AddonProperties(t *testing.T) {
 								"stage1": {
 									Addons: map[string]*ClusterAddon{
 										"addon1": {
-											Enabled:    true,
+											Enabled:    boolPtr(true),
 											Properties: map[string]any{},
 										},
 									},
@@ -877,7 +873,7 @@ func TestCluster_AddonProperties(t *testing.T) {
 			},
 			want: map[string]*ClusterAddon{
 				"addon1": {
-					Enabled:    true,
+					Enabled:    boolPtr(true),
 					Properties: map[string]any{"property1": "value1"},
 				},
 			},
@@ -888,7 +884,7 @@ func TestCluster_AddonProperties(t *testing.T) {
 				Name: "cluster1",
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled:    true,
+						Enabled:    boolPtr(true),
 						Properties: map[string]any{},
 					},
 				},
@@ -917,7 +913,7 @@ func TestCluster_AddonProperties(t *testing.T) {
 						"env1": {
 							Addons: map[string]*ClusterAddon{
 								"addon1": {
-									Enabled: true,
+									Enabled: boolPtr(true),
 									Properties: map[string]any{
 										"property1": "value1",
 									},
@@ -927,7 +923,7 @@ func TestCluster_AddonProperties(t *testing.T) {
 								"stage1": {
 									Addons: map[string]*ClusterAddon{
 										"addon1": {
-											Enabled: true,
+											Enabled: boolPtr(true),
 											Properties: map[string]any{
 												"property1": "value1",
 											},
@@ -943,7 +939,7 @@ func TestCluster_AddonProperties(t *testing.T) {
 			},
 			want: map[string]*ClusterAddon{
 				"addon1": {
-					Enabled:    true,
+					Enabled:    boolPtr(true),
 					Properties: map[string]any{"property1": "value1"},
 				},
 			},
@@ -954,7 +950,7 @@ func TestCluster_AddonProperties(t *testing.T) {
 				Name: "cluster1",
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled:    true,
+						Enabled:    boolPtr(true),
 						Properties: map[string]any{},
 					},
 				},
@@ -983,7 +979,7 @@ func TestCluster_AddonProperties(t *testing.T) {
 						"env1": {
 							Addons: map[string]*ClusterAddon{
 								"addon1": {
-									Enabled: true,
+									Enabled: boolPtr(true),
 									Properties: map[string]any{
 										"property1": "value1",
 									},
@@ -1002,11 +998,70 @@ func TestCluster_AddonProperties(t *testing.T) {
 			},
 			want: map[string]*ClusterAddon{
 				"addon1": {
-					Enabled:    true,
+					Enabled:    boolPtr(true),
 					Properties: map[string]any{"property1": "value1"},
 				},
 			},
 		},
+		{
+			name: "one addon, nested values are merged",
+			fields: fields{
+				Name: "cluster1",
+				Addons: map[string]*ClusterAddon{
+					"addon1": {
+						Properties: map[string]any{
+							"resources": map[string]any{
+								"limits": map[string]any{"memory": "1Gi", "cpu": template.DeleteMarker},
+							},
+						},
+					},
+				},
+			},
+			args: args{
+				config: &ProjectConfig{
+					Addons: map[string]Addon{
+						"addon1": {DefaultEnabled: true},
+					},
+					ParsedAddons: map[string]template.TemplateManifest{
+						"addon1": {
+							Properties: map[string]template.Property{
+								"resources": {Type: template.PropertyTypeMap},
+							},
+						},
+					},
+					Environments: map[string]*Environment{
+						"env1": {
+							Addons: map[string]*ClusterAddon{
+								"addon1": {
+									Properties: map[string]any{
+										"resources": map[string]any{
+											"limits":   map[string]any{"cpu": 1},
+											"requests": map[string]any{"cpu": 1},
+										},
+									},
+								},
+							},
+							Stages: map[string]*Stage{
+								"stage1": {},
+							},
+						},
+					},
+				},
+				env: "env1",
+				stg: "stage1",
+			},
+			want: map[string]*ClusterAddon{
+				"addon1": {
+					Enabled: boolPtr(true),
+					Properties: map[string]any{
+						"resources": map[string]any{
+							"limits":   map[string]any{"memory": "1Gi"},
+							"requests": map[string]any{"cpu": 1},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1024,3 +1079,243 @@ func TestCluster_AddonProperties(t *testing.T) {
 		})
 	}
 }
+
+func TestCluster_IsTemplateEnabled(t *testing.T) {
+	type fields struct {
+		Labels    map[string]string
+		Templates []string
+	}
+	type args struct {
+		envTemplates   []string
+		stageTemplates []string
+		manifest       template.TemplateManifest
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		want   bool
+	}{
+		{
+			name:   "nothing selected and no selector",
+			fields: fields{},
+			args: args{
+				manifest: template.TemplateManifest{Name: "appofapps"},
+			},
+			want: true,
+		},
+		{
+			name: "nothing selected and matching selector",
+			fields: fields{
+				Labels: map[string]string{"role": "hub"},
+			},
+			args: args{
+				manifest: template.TemplateManifest{Name: "hub", Selector: map[string]string{"role": "hub"}},
+			},
+			want: true,
+		},
+		{
+			name: "nothing selected and selector does not match",
+			fields: fields{
+				Labels: map[string]string{"role": "spoke"},
+			},
+			args: args{
+				manifest: template.TemplateManifest{Name: "hub", Selector: map[string]string{"role": "hub"}},
+			},
+			want: false,
+		},
+		{
+			name: "selected by cluster",
+			fields: fields{
+				Templates: []string{"hub"},
+			},
+			args: args{
+				stageTemplates: []string{},
+				manifest:       template.TemplateManifest{Name: "hub", Selector: map[string]string{"role": "hub"}},
+			},
+			want: true,
+		},
+		{
+			name: "cluster selection has precedence over stage selection",
+			fields: fields{
+				Templates: []string{},
+			},
+			args: args{
+				stageTemplates: []string{"appofapps"},
+				manifest:       template.TemplateManifest{Name: "appofapps"},
+			},
+			want: false,
+		},
+		{
+			name:   "inherited from stage",
+			fields: fields{},
+			args: args{
+				envTemplates:   []string{},
+				stageTemplates: []string{"appofapps"},
+				manifest:       template.TemplateManifest{Name: "appofapps"},
+			},
+			want: true,
+		},
+		{
+			name:   "inherited from environment",
+			fields: fields{},
+			args: args{
+				envTemplates: []string{"edge"},
+				manifest:     template.TemplateManifest{Name: "appofapps"},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &ProjectConfig{
+				Environments: map[string]*Environment{
+					"env1": {
+						Templates: tt.args.envTemplates,
+						Stages: map[string]*Stage{
+							"stage1": {
+								Templates: tt.args.stageTemplates,
+							},
+						},
+					},
+				},
+			}
+			c := &Cluster{
+				Labels:    tt.fields.Labels,
+				Templates: tt.fields.Templates,
+			}
+			if got := c.IsTemplateEnabled(config, "env1", "stage1", tt.args.manifest); got != tt.want {
+				t.Errorf("Cluster.IsTemplateEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCluster_AddonEnabled(t *testing.T) {
+	newConfig := func(defaultEnabled bool, env, stage *ClusterAddon) *ProjectConfig {
+		return &ProjectConfig{
+			Addons: map[string]Addon{
+				"monitoring": {DefaultEnabled: defaultEnabled},
+			},
+			Environments: map[string]*Environment{
+				"dev": {
+					Addons: map[string]*ClusterAddon{"monitoring": env},
+					Stages: map[string]*Stage{
+						"dev": {
+							Addons: map[string]*ClusterAddon{"monitoring": stage},
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		config  *ProjectConfig
+		cluster *ClusterAddon
+		want    bool
+	}{
+		{
+			name:   "inherit default enabled",
+			config: newConfig(true, nil, nil),
+			want:   true,
+		},
+		{
+			name:   "inherit default disabled",
+			config: newConfig(false, &ClusterAddon{}, &ClusterAddon{}),
+			want:   false,
+		},
+		{
+			name:   "enabled by environment",
+			config: newConfig(false, &ClusterAddon{Enabled: boolPtr(true)}, nil),
+			want:   true,
+		},
+		{
+			name:   "enabled by stage",
+			config: newConfig(false, nil, &ClusterAddon{Enabled: boolPtr(true)}),
+			want:   true,
+		},
+		{
+			name:   "disabled by stage",
+			config: newConfig(false, &ClusterAddon{Enabled: boolPtr(true)}, &ClusterAddon{Enabled: boolPtr(false)}),
+			want:   false,
+		},
+		{
+			name:    "cluster inherits stage",
+			config:  newConfig(false, nil, &ClusterAddon{Enabled: boolPtr(true)}),
+			cluster: &ClusterAddon{Properties: map[string]any{"key": "value"}},
+			want:    true,
+		},
+		{
+			name:    "disabled by cluster",
+			config:  newConfig(true, nil, &ClusterAddon{Enabled: boolPtr(true)}),
+			cluster: &ClusterAddon{Enabled: boolPtr(false)},
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Cluster{
+				Name:   "cluster1",
+				Addons: map[string]*ClusterAddon{},
+			}
+			if tt.cluster != nil {
+				c.Addons["monitoring"] = tt.cluster
+			}
+			if got := c.AddonEnabled(tt.config, "monitoring", "dev", "dev"); got != tt.want {
+				t.Errorf("Cluster.AddonEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCluster_resolvedAddonProperties(t *testing.T) {
+	config := &ProjectConfig{
+		Addons: map[string]Addon{
+			"monitoring": {DefaultEnabled: true},
+		},
+		ParsedAddons: map[string]template.TemplateManifest{
+			"monitoring": {
+				Properties: map[string]template.Property{
+					"ingressHost": {Type: template.PropertyTypeString, Default: "monitoring.apps.{{ .ClusterName }}.{{ .Properties.baseDomain }}"},
+					"retention":   {Type: template.PropertyTypeString, Default: "7d"},
+					"url":         {Type: template.PropertyTypeString, Default: "https://{{ .ClusterName }}"},
+				},
+			},
+		},
+		Environments: map[string]*Environment{
+			"dev": {
+				Properties: map[string]any{"baseDomain": "example.com"},
+				Stages: map[string]*Stage{
+					"dev": {
+						Addons: map[string]*ClusterAddon{
+							"monitoring": {Properties: map[string]any{"url": "https://${properties.baseDomain}"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	c := &Cluster{
+		Name:   "hugi",
+		Addons: map[string]*ClusterAddon{},
+	}
+	c.SetDefaultAddons(config)
+
+	properties, err := c.ResolvedProperties(config, "dev", "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.resolvedAddonProperties(config, "monitoring", "dev", "dev", properties)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"ingressHost": "monitoring.apps.hugi.example.com",
+		"retention":   "7d",
+		"url":         "https://example.com",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Cluster.resolvedAddonProperties() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -44,6 +44,26 @@ func ParseConfig(path string) (*ProjectConfig, error) {
 		tm.Group = v.Group
 		pc.ParsedAddons[k] = *tm
 	}
+
+	err = pc.ValidateNames()
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred while validating the names: %w", err)
+	}
+
+	err = pc.ValidatePolicies()
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred while validating the policies: %w", err)
+	}
+
+	err = pc.ValidateProperties()
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred while validating the properties: %w", err)
+	}
+
+	err = pc.EncryptSecrets()
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred while encrypting the secret properties: %w", err)
+	}
 	return pc, nil
 }
 
@@ -0,0 +1,397 @@
+package project
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
+)
+
+// EntityKind is the kind of an entity of the project that can be addressed by a path
+type EntityKind string
+
+const (
+	EntityKindEnvironment EntityKind = "environment"
+	EntityKindStage       EntityKind = "stage"
+	EntityKindCluster     EntityKind = "cluster"
+)
+
+// EntityRef addresses an environment (<env>), a stage (<env>/<stage>) or a cluster (<env>/<stage>/<cluster>)
+type EntityRef struct {
+	Kind        EntityKind
+	Environment string
+	Stage       string
+	Cluster     string
+}
+
+// ParseEntityRef parses the kind and the path of an entity
+// The kinds env and environment are equivalent
+func ParseEntityRef(kind, path string) (EntityRef, error) {
+	segments := strings.Split(path, "/")
+	want := 0
+	switch EntityKind(kind) {
+	case EntityKindEnvironment, "env":
+		kind, want = string(EntityKindEnvironment), 1
+	case EntityKindStage:
+		want = 2
+	case EntityKindCluster:
+		want = 3
+	default:
+		return EntityRef{}, fmt.Errorf("unknown kind %q, must be one of environment, stage or cluster", kind)
+	}
+	if len(segments) != want || strings.Contains(path, "//") || strings.HasPrefix(path, "/") || strings.HasSuffix(path, "/") {
+		return EntityRef{}, fmt.Errorf("invalid %s path %q, expected %s", kind, path, strings.Join([]string{"<env>", "<stage>", "<cluster>"}[:want], "/"))
+	}
+	ref := EntityRef{Kind: EntityKind(kind), Environment: segments[0]}
+	if want > 1 {
+		ref.Stage = segments[1]
+	}
+	if want > 2 {
+		ref.Cluster = segments[2]
+	}
+	return ref, nil
+}
+
+func (r EntityRef) String() string {
+	switch r.Kind {
+	case EntityKindStage:
+		return r.Environment + "/" + r.Stage
+	case EntityKindCluster:
+		return r.Environment + "/" + r.Stage + "/" + r.Cluster
+	default:
+		return r.Environment
+	}
+}
+
+// entity is the state of an addressed entity that can be changed
+type entity struct {
+	value      any
+	properties *map[string]any
+	addons     AddonHandler
+	// level and parents are used to check the required properties like the menus do
+	level   PropertyLevel
+	parents PropertyLayers
+}
+
+// entity resolves the reference, an error is returned if the entity does not exist
+func (p *ProjectConfig) entity(ref EntityRef) (*entity, error) {
+	if !p.HasEnvironment(ref.Environment) {
+		return nil, fmt.Errorf("environment %q does not exist", ref.Environment)
+	}
+	env := p.GetEnvironment(ref.Environment)
+	if ref.Kind == EntityKindEnvironment {
+		return &entity{value: env, properties: &env.Properties, addons: env, level: PropertyLevelEnvironment, parents: p.PropertyLayersAt("", "")}, nil
+	}
+	if !env.HasStage(ref.Stage) {
+		return nil, fmt.Errorf("stage %q does not exist in environment %s", ref.Stage, ref.Environment)
+	}
+	stage := env.GetStage(ref.Stage)
+	if ref.Kind == EntityKindStage {
+		return &entity{value: stage, properties: &stage.Properties, addons: stage, level: PropertyLevelStage, parents: p.PropertyLayersAt(ref.Environment, "")}, nil
+	}
+	if !p.HasCluster(ref.Environment, ref.Stage, ref.Cluster) {
+		return nil, fmt.Errorf("cluster %q does not exist in %s/%s", ref.Cluster, ref.Environment, ref.Stage)
+	}
+	cluster := p.GetCluster(ref.Environment, ref.Stage, ref.Cluster)
+	if cluster.Name == "" {
+		cluster.Name = ref.Cluster
+	}
+	return &entity{value: cluster, properties: &cluster.Properties, addons: cluster, level: PropertyLevelCluster, parents: p.PropertyLayersAt(ref.Environment, ref.Stage)}, nil
+}
+
+// Entity returns the addressed environment, stage or cluster
+func (p *ProjectConfig) Entity(ref EntityRef) (any, error) {
+	e, err := p.entity(ref)
+	if err != nil {
+		return nil, err
+	}
+	return e.value, nil
+}
+
+// MaskedEntity returns a copy of the addressed environment, stage or cluster for display
+// The values of secret properties are masked, including the ones of its stages, clusters and addons
+func (p *ProjectConfig) MaskedEntity(ref EntityRef) (any, error) {
+	value, err := p.Entity(ref)
+	if err != nil {
+		return nil, err
+	}
+	switch v := value.(type) {
+	case *Environment:
+		return p.maskedEnvironment(v), nil
+	case *Stage:
+		return p.maskedStage(v), nil
+	case *Cluster:
+		return p.maskedCluster(v), nil
+	}
+	return value, nil
+}
+
+func (p *ProjectConfig) maskedEnvironment(env *Environment) *Environment {
+	masked := *env
+	masked.Properties = maskSecretValues(p.PropertyDefinitions(), env.Properties)
+	masked.Addons = p.maskedAddons(env.Addons)
+	if env.Stages != nil {
+		masked.Stages = make(map[string]*Stage, len(env.Stages))
+		for name, stage := range env.Stages {
+			masked.Stages[name] = p.maskedStage(stage)
+		}
+	}
+	return &masked
+}
+
+func (p *ProjectConfig) maskedStage(stage *Stage) *Stage {
+	if stage == nil {
+		return nil
+	}
+	masked := *stage
+	masked.Properties = maskSecretValues(p.PropertyDefinitions(), stage.Properties)
+	masked.Addons = p.maskedAddons(stage.Addons)
+	if stage.Clusters != nil {
+		masked.Clusters = make(map[string]*Cluster, len(stage.Clusters))
+		for name, cluster := range stage.Clusters {
+			masked.Clusters[name] = p.maskedCluster(cluster)
+		}
+	}
+	return &masked
+}
+
+func (p *ProjectConfig) maskedCluster(cluster *Cluster) *Cluster {
+	if cluster == nil {
+		return nil
+	}
+	masked := *cluster
+	masked.Properties = maskSecretValues(p.PropertyDefinitions(), cluster.Properties)
+	masked.Addons = p.maskedAddons(cluster.Addons)
+	return &masked
+}
+
+func (p *ProjectConfig) maskedAddons(addons map[string]*ClusterAddon) map[string]*ClusterAddon {
+	if addons == nil {
+		return nil
+	}
+	masked := make(map[string]*ClusterAddon, len(addons))
+	for name, addon := range addons {
+		if addon == nil {
+			masked[name] = nil
+			continue
+		}
+		copied := *addon
+		copied.Properties = maskSecretValues(p.ParsedAddons[name].Properties, addon.Properties)
+		masked[name] = &copied
+	}
+	return masked
+}
+
+// SetEntityProperty parses the value against the property schema and sets it on the entity
+// Secrets are encrypted and the required properties of the level are checked, like the property menu does
+func (p *ProjectConfig) SetEntityProperty(ref EntityRef, key string, value any) error {
+	e, err := p.entity(ref)
+	if err != nil {
+		return err
+	}
+	value, err = p.ParsePropertyValue(key, value)
+	if err != nil {
+		return fmt.Errorf("property %s: %w", key, err)
+	}
+	if p.PropertyDefinitions()[key].Type == template.PropertyTypeSecret {
+		value, err = p.EncryptSecret(value)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt the secret %s: %w", key, err)
+		}
+	}
+	if *e.properties == nil {
+		*e.properties = map[string]any{}
+	}
+	(*e.properties)[key] = value
+	return p.checkEntity(ref, e)
+}
+
+// UnsetEntityProperty removes the property from the entity, so the value is inherited again
+func (p *ProjectConfig) UnsetEntityProperty(ref EntityRef, key string) error {
+	e, err := p.entity(ref)
+	if err != nil {
+		return err
+	}
+	if _, ok := (*e.properties)[key]; !ok {
+		return fmt.Errorf("property %s is not set on %s %s", key, ref.Kind, ref)
+	}
+	delete(*e.properties, key)
+	return p.checkEntity(ref, e)
+}
+
+// SetEntityAddon sets a field of the addon configuration of the entity
+// The field is either enabled or properties.<key>, property values are parsed against the addon manifest
+func (p *ProjectConfig) SetEntityAddon(ref EntityRef, addon, field string, value any) error {
+	e, err := p.entity(ref)
+	if err != nil {
+		return err
+	}
+	manifest, ok := p.ParsedAddons[addon]
+	if !ok {
+		return fmt.Errorf("addon %q does not exist", addon)
+	}
+	addons := e.addons.GetAddons()
+	if addons == nil {
+		addons = ClusterAddons{}
+		setEntityAddons(e.value, addons)
+	}
+	if addons[addon] == nil {
+		// properties can be set without changing the enablement
+		addons[addon] = &ClusterAddon{}
+	}
+
+	switch {
+	case field == "enabled":
+		enabled, err := parseBool(value)
+		if err != nil {
+			return fmt.Errorf("addon %s: %w", addon, err)
+		}
+		if enabled {
+			e.addons.EnableAddon(addon)
+		} else {
+			e.addons.DisableAddon(addon)
+		}
+	case strings.HasPrefix(field, "properties."):
+		key := strings.TrimPrefix(field, "properties.")
+		property, ok := manifest.Properties[key]
+		if !ok {
+			return fmt.Errorf("addon %s has no property %s", addon, key)
+		}
+		value, err = property.ParseValue(value)
+		if err != nil {
+			return fmt.Errorf("property %s of addon %s: %w", key, addon, err)
+		}
+		if property.Type == template.PropertyTypeSecret {
+			value, err = p.EncryptSecret(value)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt the secret %s of addon %s: %w", key, addon, err)
+			}
+		}
+		addons[addon].SetProperty(key, value)
+	default:
+		return fmt.Errorf("unknown addon field %q, must be enabled or properties.<key>", field)
+	}
+
+	if e.addons.IsAddonEnabled(addon) {
+		// check if all required properties are set
+		err := e.addons.GetAddon(addon).AllRequiredPropertiesSet(p, addon)
+		if err != nil {
+			return err
+		}
+	}
+	return p.checkEntity(ref, e)
+}
+
+// UnsetEntityAddon removes a field of the addon configuration of the entity
+// If the field is empty, the whole addon configuration is removed, unsetting enabled inherits the enablement
+func (p *ProjectConfig) UnsetEntityAddon(ref EntityRef, addon, field string) error {
+	e, err := p.entity(ref)
+	if err != nil {
+		return err
+	}
+	ca := e.addons.GetAddon(addon)
+	if ca == nil {
+		return fmt.Errorf("addon %s is not configured on %s %s", addon, ref.Kind, ref)
+	}
+
+	switch {
+	case field == "":
+		delete(e.addons.GetAddons(), addon)
+	case field == "enabled":
+		e.addons.InheritAddon(addon)
+	case strings.HasPrefix(field, "properties."):
+		key := strings.TrimPrefix(field, "properties.")
+		if _, ok := ca.Properties[key]; !ok {
+			return fmt.Errorf("property %s of addon %s is not set on %s %s", key, addon, ref.Kind, ref)
+		}
+		delete(ca.Properties, key)
+		if e.addons.IsAddonEnabled(addon) {
+			err := ca.AllRequiredPropertiesSet(p, addon)
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unknown addon field %q, must be enabled or properties.<key>", field)
+	}
+	return p.checkEntity(ref, e)
+}
+
+// checkEntity checks the changed entity like the menus do before it is saved
+// Clusters get their generated properties and must comply with the policies of the project
+func (p *ProjectConfig) checkEntity(ref EntityRef, e *entity) error {
+	merged := append(e.parents, PropertyLayer{Origin: PropertyOrigin(e.level), Properties: *e.properties}).Merge(p.PropertyDefinitions())
+	err := p.CheckRequiredProperties(e.level, merged)
+	if err != nil {
+		return err
+	}
+
+	cluster, ok := e.value.(*Cluster)
+	if !ok {
+		// the clusters of the environment or stage inherit the change, so they must still comply with the policies
+		violations, err := p.checkChildPolicies(ref)
+		if err != nil {
+			return fmt.Errorf("failed to check policies: %w", err)
+		}
+		return PolicyError(violations)
+	}
+	err = p.GenerateProperties(ref.Environment, ref.Stage, cluster)
+	if err != nil {
+		return err
+	}
+	violations, err := p.CheckClusterPolicies(ref.Environment, ref.Stage, cluster)
+	if err != nil {
+		return fmt.Errorf("failed to check policies: %w", err)
+	}
+	return PolicyError(violations)
+}
+
+// checkChildPolicies checks the policies of all clusters of the environment or stage
+func (p *ProjectConfig) checkChildPolicies(ref EntityRef) ([]PolicyViolation, error) {
+	env := p.GetEnvironment(ref.Environment)
+	stages := utils.SortStringSlice(utils.MapKeysToList(env.Stages))
+	if ref.Kind == EntityKindStage {
+		stages = []string{ref.Stage}
+	}
+	violations := []PolicyViolation{}
+	for _, stageName := range stages {
+		clusters := env.Stages[stageName].Clusters
+		for _, clusterName := range utils.SortStringSlice(utils.MapKeysToList(clusters)) {
+			// the name is taken from the map key without modifying the cluster of the project
+			cluster := *clusters[clusterName]
+			cluster.Name = clusterName
+			result, err := p.CheckClusterPolicies(ref.Environment, stageName, &cluster)
+			if err != nil {
+				return nil, fmt.Errorf("cluster %s/%s/%s: %w", ref.Environment, stageName, clusterName, err)
+			}
+			violations = append(violations, result...)
+		}
+	}
+	return violations, nil
+}
+
+// setEntityAddons sets the addon configurations of the environment, stage or cluster
+func setEntityAddons(value any, addons ClusterAddons) {
+	switch v := value.(type) {
+	case *Environment:
+		v.Addons = addons
+	case *Stage:
+		v.Addons = addons
+	case *Cluster:
+		v.Addons = addons
+	}
+}
+
+// parseBool parses a boolean given as bool or string
+func parseBool(value any) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(v)
+	default:
+		return false, fmt.Errorf("value %v is not a boolean", value)
+	}
+}
@@ -0,0 +1,295 @@
+package project
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/secret"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+)
+
+func TestParseEntityRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		path    string
+		want    EntityRef
+		wantErr bool
+	}{
+		{
+			name: "environment",
+			kind: "env",
+			path: "dev",
+			want: EntityRef{Kind: EntityKindEnvironment, Environment: "dev"},
+		},
+		{
+			name: "stage",
+			kind: "stage",
+			path: "dev/qa",
+			want: EntityRef{Kind: EntityKindStage, Environment: "dev", Stage: "qa"},
+		},
+		{
+			name: "cluster",
+			kind: "cluster",
+			path: "dev/qa/hugi",
+			want: EntityRef{Kind: EntityKindCluster, Environment: "dev", Stage: "qa", Cluster: "hugi"},
+		},
+		{
+			name:    "unknown kind",
+			kind:    "addon",
+			path:    "dev",
+			wantErr: true,
+		},
+		{
+			name:    "path too short",
+			kind:    "cluster",
+			path:    "dev/qa",
+			wantErr: true,
+		},
+		{
+			name:    "empty segment",
+			kind:    "stage",
+			path:    "dev/",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseEntityRef(tt.kind, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseEntityRef() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseEntityRef() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProjectConfig_SetEntity(t *testing.T) {
+	newConfig := func() *ProjectConfig {
+		return &ProjectConfig{
+			PropertySchema: map[string]PropertyDefinition{
+				"replicas": {Property: template.Property{Type: template.PropertyTypeInt}},
+				"region":   {Property: template.Property{Type: template.PropertyTypeString, Required: true}, RequiredAt: PropertyLevelStage},
+				"token":    {Property: template.Property{Type: template.PropertyTypeSecret}},
+			},
+			Addons: map[string]Addon{
+				"monitoring": {DefaultEnabled: false},
+			},
+			ParsedAddons: map[string]template.TemplateManifest{
+				"monitoring": {Name: "monitoring", Group: "apps", Properties: map[string]template.Property{
+					"enforce":   {Type: template.PropertyTypeBool, Default: false},
+					"retention": {Type: template.PropertyTypeString, Required: true},
+				}},
+			},
+			Environments: map[string]*Environment{
+				"dev": {Stages: map[string]*Stage{
+					"dev": {
+						Properties: map[string]any{"region": "eu"},
+						Clusters: map[string]*Cluster{
+							"hugi": {Properties: map[string]any{"replicas": 1}},
+						},
+					},
+				}},
+			},
+		}
+	}
+	cluster := EntityRef{Kind: EntityKindCluster, Environment: "dev", Stage: "dev", Cluster: "hugi"}
+	stage := EntityRef{Kind: EntityKindStage, Environment: "dev", Stage: "dev"}
+
+	tests := []struct {
+		name    string
+		change  func(p *ProjectConfig) error
+		want    any
+		get     func(p *ProjectConfig) any
+		wantErr bool
+	}{
+		{
+			name:   "set property",
+			change: func(p *ProjectConfig) error { return p.SetEntityProperty(cluster, "replicas", "3") },
+			get:    func(p *ProjectConfig) any { return p.GetCluster("dev", "dev", "hugi").Properties["replicas"] },
+			want:   3,
+		},
+		{
+			name:    "set property of the wrong type",
+			change:  func(p *ProjectConfig) error { return p.SetEntityProperty(cluster, "replicas", "many") },
+			wantErr: true,
+		},
+		{
+			name:    "set secret without key",
+			change:  func(p *ProjectConfig) error { return p.SetEntityProperty(cluster, "token", "s3cr3t") },
+			wantErr: true,
+		},
+		{
+			name: "set property of a missing cluster",
+			change: func(p *ProjectConfig) error {
+				return p.SetEntityProperty(EntityRef{Kind: EntityKindCluster, Environment: "dev", Stage: "dev", Cluster: "odin"}, "replicas", "3")
+			},
+			wantErr: true,
+		},
+		{
+			name:   "unset property",
+			change: func(p *ProjectConfig) error { return p.UnsetEntityProperty(cluster, "replicas") },
+			get:    func(p *ProjectConfig) any { return p.GetCluster("dev", "dev", "hugi").Properties },
+			want:   map[string]any{},
+		},
+		{
+			name:    "unset required property",
+			change:  func(p *ProjectConfig) error { return p.UnsetEntityProperty(stage, "region") },
+			wantErr: true,
+		},
+		{
+			name:    "enable addon without required properties",
+			change:  func(p *ProjectConfig) error { return p.SetEntityAddon(cluster, "monitoring", "enabled", "true") },
+			wantErr: true,
+		},
+		{
+			name: "enable addon with required properties",
+			change: func(p *ProjectConfig) error {
+				err := p.SetEntityAddon(stage, "monitoring", "properties.retention", "7d")
+				if err != nil {
+					return err
+				}
+				return p.SetEntityAddon(stage, "monitoring", "enabled", "true")
+			},
+			get: func(p *ProjectConfig) any { return p.GetStage("dev", "dev").Addons["monitoring"] },
+			want: &ClusterAddon{
+				Enabled:    boolPtr(true),
+				Properties: map[string]any{"retention": "7d"},
+			},
+		},
+		{
+			name:    "set unknown addon property",
+			change:  func(p *ProjectConfig) error { return p.SetEntityAddon(stage, "monitoring", "properties.unknown", "x") },
+			wantErr: true,
+		},
+		{
+			name: "set addon property of the wrong type",
+			change: func(p *ProjectConfig) error {
+				return p.SetEntityAddon(stage, "monitoring", "properties.enforce", "maybe")
+			},
+			wantErr: true,
+		},
+		{
+			name:    "set unknown addon",
+			change:  func(p *ProjectConfig) error { return p.SetEntityAddon(stage, "logging", "enabled", "true") },
+			wantErr: true,
+		},
+		{
+			name: "unset addon",
+			change: func(p *ProjectConfig) error {
+				err := p.SetEntityAddon(cluster, "monitoring", "properties.enforce", "true")
+				if err != nil {
+					return err
+				}
+				return p.UnsetEntityAddon(cluster, "monitoring", "")
+			},
+			get:  func(p *ProjectConfig) any { return p.GetCluster("dev", "dev", "hugi").Addons },
+			want: map[string]*ClusterAddon{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newConfig()
+			err := tt.change(p)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("change error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.get == nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, tt.get(p)); diff != "" {
+				t.Errorf("change mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProjectConfig_MaskedEntity(t *testing.T) {
+	pc := &ProjectConfig{
+		PropertySchema: map[string]PropertyDefinition{
+			"region": {Property: template.Property{Type: template.PropertyTypeString}},
+			"token":  {Property: template.Property{Type: template.PropertyTypeSecret}},
+		},
+		ParsedAddons: map[string]template.TemplateManifest{
+			"monitoring": {Properties: map[string]template.Property{
+				"password": {Type: template.PropertyTypeSecret},
+			}},
+		},
+		Environments: map[string]*Environment{
+			"dev": {
+				Properties: map[string]any{"region": "eu", "token": "ENC[secretbox,AAAA]"},
+				Stages: map[string]*Stage{
+					"dev": {Clusters: map[string]*Cluster{
+						"hugi": {
+							Properties: map[string]any{"token": "plain"},
+							Addons:     map[string]*ClusterAddon{"monitoring": {Properties: map[string]any{"password": "plain"}}},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	got, err := pc.MaskedEntity(EntityRef{Kind: EntityKindEnvironment, Environment: "dev"})
+	if err != nil {
+		t.Fatalf("ProjectConfig.MaskedEntity() error = %v", err)
+	}
+	want := &Environment{
+		Name:       "dev",
+		Properties: map[string]any{"region": "eu", "token": secret.Mask},
+		Stages: map[string]*Stage{
+			"dev": {Clusters: map[string]*Cluster{
+				"hugi": {
+					Properties: map[string]any{"token": secret.Mask},
+					Addons:     map[string]*ClusterAddon{"monitoring": {Properties: map[string]any{"password": secret.Mask}}},
+				},
+			}},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ProjectConfig.MaskedEntity() mismatch (-want +got):\n%s", diff)
+	}
+	if pc.GetCluster("dev", "dev", "hugi").Properties["token"] != "plain" {
+		t.Errorf("ProjectConfig.MaskedEntity() must not modify the project")
+	}
+}
+
+func TestProjectConfig_SetEntityAddon_childPolicies(t *testing.T) {
+	tests := []struct {
+		name string
+		ref  EntityRef
+	}{
+		{name: "environment", ref: EntityRef{Kind: EntityKindEnvironment, Environment: "dev"}},
+		{name: "stage", ref: EntityRef{Kind: EntityKindStage, Environment: "dev", Stage: "dev"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pc := &ProjectConfig{
+				Policies: []Policy{{Name: "monitoring", RequiredAddons: []string{"monitoring"}}},
+				Addons:   map[string]Addon{"monitoring": {DefaultEnabled: true}},
+				ParsedAddons: map[string]template.TemplateManifest{
+					"monitoring": {Name: "monitoring", Group: "apps"},
+				},
+				Environments: map[string]*Environment{
+					"dev": {Stages: map[string]*Stage{
+						"dev": {Clusters: map[string]*Cluster{"hugi": {}}},
+						"qa":  {Clusters: map[string]*Cluster{}},
+					}},
+				},
+			}
+			err := pc.SetEntityAddon(tt.ref, "monitoring", "enabled", "false")
+			if err == nil {
+				t.Fatal("ProjectConfig.SetEntityAddon() expected a policy violation of the child cluster")
+			}
+			if !strings.Contains(err.Error(), "dev/dev/hugi") {
+				t.Errorf("ProjectConfig.SetEntityAddon() error = %v, want a violation of dev/dev/hugi", err)
+			}
+			if pc.GetCluster("dev", "dev", "hugi").Name != "" {
+				t.Errorf("ProjectConfig.SetEntityAddon() must not modify the child clusters")
+			}
+		})
+	}
+}
@@ -1,44 +1,58 @@
 package project
 
+import (
+	"fmt"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+)
+
 var (
-	_ AddonHandler = &Environment{}
+	_ AddonHandler    = &Environment{}
+	_ TemplateHandler = &Environment{}
 )
 
 type Environment struct {
 	Name       string                   `json:"-"`
-	Properties map[string]string        `json:"properties"`
+	Properties map[string]any           `json:"properties"`
 	Actions    Actions                  `json:"actions"`
 	Stages     map[string]*Stage        `json:"stages"`
 	Addons     map[string]*ClusterAddon `json:"addons"`
+	// Templates is the list of base templates that are rendered for the clusters of the environment
+	// If nil, all templates are rendered whose selector matches the cluster labels
+	Templates []string `json:"templates"`
+	// SealedSecretsCertificate is the location of the certificate of the sealed-secrets controller of the environment
+	// It is used by the seal template functions to encrypt values offline
+	SealedSecretsCertificate string `json:"sealedSecretsCertificate,omitempty"`
 }
 
-// IsAddonEnabled checks if the addon is enabled for the stage
+// IsAddonEnabled checks if the addon has been enabled explicitly for the environment
 func (e Environment) IsAddonEnabled(addon string) bool {
-	_, ok := e.Addons[addon]
-	if !ok {
-		return false
-	}
-	return e.Addons[addon].Enabled
+	return e.Addons[addon].State() == AddonStateEnabled
 }
 
-// EnableAddon enables the addon for the stage by setting the enabled flag to true
+// EnableAddon enables the addon for the environment by setting the enabled flag to true
 func (e *Environment) EnableAddon(addon string) {
 	if e.Addons[addon] == nil {
-		e.Addons[addon] = &ClusterAddon{
-			Enabled: true,
-		}
-		return
+		e.Addons[addon] = &ClusterAddon{}
 	}
-	e.Addons[addon].Enabled = true
+	e.Addons[addon].Enabled = boolPtr(true)
 }
 
-// DisableAddon disables the addon for the stage by setting the enabled flag to false
+// DisableAddon disables the addon for the environment by setting the enabled flag to false
 func (e *Environment) DisableAddon(addon string) {
-	if _, ok := e.Addons[addon]; !ok {
-		// already disabled or not found
+	if e.Addons[addon] == nil {
+		e.Addons[addon] = &ClusterAddon{}
+	}
+	e.Addons[addon].Enabled = boolPtr(false)
+}
+
+// InheritAddon resets the enabled flag of the addon, so that the enablement is inherited from the parent
+func (e *Environment) InheritAddon(addon string) {
+	if e.Addons[addon] == nil {
+		// already inherited
 		return
 	}
-	e.Addons[addon].Enabled = false
+	e.Addons[addon].Enabled = nil
 }
 
 // GetAddons returns the environment addons
@@ -51,6 +65,16 @@ func (e *Environment) GetAddon(name string) *ClusterAddon {
 	return e.Addons[name]
 }
 
+// GetTemplates returns the base templates selected for the environment
+func (e *Environment) GetTemplates() []string {
+	return e.Templates
+}
+
+// SetTemplates sets the base templates selected for the environment
+func (e *Environment) SetTemplates(templates []string) {
+	e.Templates = templates
+}
+
 // HasStage checks if a stage exists in the environment
 func (e *Environment) HasStage(name string) bool {
 	_, ok := e.Stages[name]
@@ -61,3 +85,26 @@ func (e *Environment) HasStage(name string) bool {
 func (e *Environment) GetStage(name string) *Stage {
 	return e.Stages[name]
 }
+
+// Render renders all environment scoped templates into <basePath>/<env> and returns the rendered files
+func (e *Environment) Render(config *ProjectConfig) ([]template.RenderedFile, error) {
+	properties, err := config.decryptSecrets(config.PropertyDefinitions(), config.PropertiesAt(e.Properties))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt properties of environment %s: %w", e.Name, err)
+	}
+	properties, err = interpolateProperties(interpolationScope{Environment: e.Name}, properties)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve properties of environment %s: %w", e.Name, err)
+	}
+	sealingKey, err := config.SealingKey(e.Name)
+	if err != nil {
+		return nil, err
+	}
+	return renderScopedTemplates(config, template.TemplateScopeEnvironment, template.TemplateData{
+		BasePath:      config.BasePath,
+		Environment:   e.Name,
+		Properties:    properties,
+		ClusterSource: config.clusterSource(),
+		SealingKey:    sealingKey,
+	})
+}
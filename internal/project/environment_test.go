@@ -11,7 +11,7 @@ func TestEnvironment_IsAddonEnabled(t *testing.T) {
 	type fields struct {
 		Name       string
 		Addons     map[string]*ClusterAddon
-		Properties map[string]string
+		Properties map[string]any
 	}
 	type args struct {
 		addon string
@@ -27,7 +27,7 @@ func TestEnvironment_IsAddonEnabled(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -41,7 +41,7 @@ func TestEnvironment_IsAddonEnabled(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -65,10 +65,10 @@ func TestEnvironment_IsAddonEnabled(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -82,10 +82,10 @@ func TestEnvironment_IsAddonEnabled(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -99,10 +99,10 @@ func TestEnvironment_IsAddonEnabled(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 					"addon2": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -116,10 +116,10 @@ func TestEnvironment_IsAddonEnabled(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -147,7 +147,7 @@ func TestEnvironment_EnableAddon(t *testing.T) {
 	type fields struct {
 		Name       string
 		Addons     map[string]*ClusterAddon
-		Properties map[string]string
+		Properties map[string]any
 	}
 	type args struct {
 		addon string
@@ -169,7 +169,7 @@ func TestEnvironment_EnableAddon(t *testing.T) {
 			want: Environment{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -179,7 +179,7 @@ func TestEnvironment_EnableAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -189,7 +189,7 @@ func TestEnvironment_EnableAddon(t *testing.T) {
 			want: Environment{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -199,7 +199,7 @@ func TestEnvironment_EnableAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -209,7 +209,7 @@ func TestEnvironment_EnableAddon(t *testing.T) {
 			want: Environment{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -219,10 +219,10 @@ func TestEnvironment_EnableAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -232,10 +232,10 @@ func TestEnvironment_EnableAddon(t *testing.T) {
 			want: Environment{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -245,10 +245,10 @@ func TestEnvironment_EnableAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 					"addon2": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -258,10 +258,10 @@ func TestEnvironment_EnableAddon(t *testing.T) {
 			want: Environment{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -289,7 +289,7 @@ func TestEnvironment_DisableAddon(t *testing.T) {
 	type fields struct {
 		Name       string
 		Addons     map[string]*ClusterAddon
-		Properties map[string]string
+		Properties map[string]any
 	}
 	type args struct {
 		addon string
@@ -309,7 +309,9 @@ func TestEnvironment_DisableAddon(t *testing.T) {
 				addon: "addon1",
 			},
 			want: Environment{
-				Addons: map[string]*ClusterAddon{},
+				Addons: map[string]*ClusterAddon{
+					"addon1": {Enabled: boolPtr(false)},
+				},
 			},
 		},
 		{
@@ -317,7 +319,7 @@ func TestEnvironment_DisableAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled:    true,
+						Enabled:    boolPtr(true),
 						Properties: map[string]any{},
 					},
 				},
@@ -328,7 +330,7 @@ func TestEnvironment_DisableAddon(t *testing.T) {
 			want: Environment{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled:    false,
+						Enabled:    boolPtr(false),
 						Properties: map[string]any{},
 					},
 				},
@@ -339,7 +341,7 @@ func TestEnvironment_DisableAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -349,7 +351,7 @@ func TestEnvironment_DisableAddon(t *testing.T) {
 			want: Environment{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled:    false,
+						Enabled:    boolPtr(false),
 						Properties: nil,
 					},
 				},
@@ -377,7 +379,7 @@ func TestEnvironment_DisableAddon(t *testing.T) {
 func TestEnvironment_GetAddons(t *testing.T) {
 	type fields struct {
 		Name       string
-		Properties map[string]string
+		Properties map[string]any
 		Actions    Actions
 		Stages     map[string]*Stage
 		Addons     map[string]*ClusterAddon
@@ -399,13 +401,13 @@ func TestEnvironment_GetAddons(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
 			want: map[string]*ClusterAddon{
 				"addon1": {
-					Enabled: true,
+					Enabled: boolPtr(true),
 				},
 			},
 		},
@@ -414,19 +416,19 @@ func TestEnvironment_GetAddons(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
 			want: map[string]*ClusterAddon{
 				"addon1": {
-					Enabled: true,
+					Enabled: boolPtr(true),
 				},
 				"addon2": {
-					Enabled: false,
+					Enabled: boolPtr(false),
 				},
 			},
 		},
@@ -450,7 +452,7 @@ func TestEnvironment_GetAddons(t *testing.T) {
 func TestEnvironment_GetAddon(t *testing.T) {
 	type fields struct {
 		Name       string
-		Properties map[string]string
+		Properties map[string]any
 		Actions    Actions
 		Stages     map[string]*Stage
 		Addons     map[string]*ClusterAddon
@@ -479,7 +481,7 @@ func TestEnvironment_GetAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -487,7 +489,7 @@ func TestEnvironment_GetAddon(t *testing.T) {
 				name: "addon1",
 			},
 			want: &ClusterAddon{
-				Enabled: true,
+				Enabled: boolPtr(true),
 			},
 		},
 		{
@@ -495,10 +497,10 @@ func TestEnvironment_GetAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -506,7 +508,7 @@ func TestEnvironment_GetAddon(t *testing.T) {
 				name: "addon1",
 			},
 			want: &ClusterAddon{
-				Enabled: true,
+				Enabled: boolPtr(true),
 			},
 		},
 		{
@@ -514,10 +516,10 @@ func TestEnvironment_GetAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -546,7 +548,7 @@ func TestEnvironment_GetAddon(t *testing.T) {
 func TestEnvironment_HasStage(t *testing.T) {
 	type fields struct {
 		Name       string
-		Properties map[string]string
+		Properties map[string]any
 		Actions    Actions
 		Stages     map[string]*Stage
 		Addons     map[string]*ClusterAddon
@@ -615,7 +617,7 @@ func TestEnvironment_HasStage(t *testing.T) {
 func TestEnvironment_GetStage(t *testing.T) {
 	type fields struct {
 		Name       string
-		Properties map[string]string
+		Properties map[string]any
 		Actions    Actions
 		Stages     map[string]*Stage
 		Addons     map[string]*ClusterAddon
@@ -0,0 +1,136 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/secret"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
+)
+
+// GenerateProperties generates the values of all properties with a generate strategy that have not been set on the cluster
+// This includes the project properties and the properties of the addons enabled for the cluster
+// The generated values are unique across all clusters of the project, generated secrets are encrypted if a key is configured
+func (p *ProjectConfig) GenerateProperties(env, stage string, cluster *Cluster) error {
+	schema := p.PropertyDefinitions()
+	for _, key := range utils.SortStringSlice(utils.MapKeysToList(schema)) {
+		if schema[key].Generate == nil {
+			continue
+		}
+		if cluster.Properties == nil {
+			cluster.Properties = map[string]any{}
+		}
+		if _, ok := cluster.Properties[key]; ok {
+			continue
+		}
+		value, err := p.generateValue(env, stage, cluster, "", key, schema[key])
+		if err != nil {
+			return fmt.Errorf("failed to generate property %s: %w", key, err)
+		}
+		cluster.Properties[key] = value
+	}
+
+	for _, addonName := range utils.SortStringSlice(utils.MapKeysToList(p.ParsedAddons)) {
+		if !cluster.AddonEnabled(p, addonName, env, stage) {
+			continue
+		}
+		properties := p.ParsedAddons[addonName].Properties
+		for _, key := range utils.SortStringSlice(utils.MapKeysToList(properties)) {
+			if properties[key].Generate == nil {
+				continue
+			}
+			if cluster.Addons == nil {
+				cluster.Addons = map[string]*ClusterAddon{}
+			}
+			if cluster.Addons[addonName] == nil {
+				cluster.Addons[addonName] = &ClusterAddon{}
+			}
+			if _, ok := cluster.Addons[addonName].Properties[key]; ok {
+				continue
+			}
+			value, err := p.generateValue(env, stage, cluster, addonName, key, properties[key])
+			if err != nil {
+				return fmt.Errorf("failed to generate property %s of addon %s: %w", key, addonName, err)
+			}
+			cluster.Addons[addonName].SetProperty(key, value)
+		}
+	}
+	return nil
+}
+
+// generateValue generates the value of the property, the addon is empty for project properties
+func (p *ProjectConfig) generateValue(env, stage string, cluster *Cluster, addon, key string, property template.Property) (any, error) {
+	existing, err := p.allocatedValues(env, stage, cluster, addon, key, property)
+	if err != nil {
+		return nil, err
+	}
+	value, err := property.GenerateValue(existing)
+	if err != nil {
+		return nil, err
+	}
+	if property.Type != template.PropertyTypeSecret {
+		return value, nil
+	}
+	encrypted, err := p.EncryptSecret(value)
+	if errors.Is(err, secret.ErrNoKey) {
+		// kept in plain text, like all other secrets without a key
+		return value, nil
+	}
+	return encrypted, err
+}
+
+// allocatedValues returns the effective values of the property of all clusters of the project, including the values they inherit
+// Networks of cidr properties whose pools overlap must not overlap either, so the values of these properties are returned as well
+// Secrets are decrypted, so they can be compared with the generated value
+func (p *ProjectConfig) allocatedValues(env, stage string, cluster *Cluster, addon, key string, property template.Property) ([]any, error) {
+	values := []any{}
+	collect := func(schema map[string]template.Property, layers PropertyLayers, addonName string) error {
+		properties, err := p.decryptSecrets(schema, layers.Merge(schema))
+		if err != nil {
+			return err
+		}
+		for k, v := range properties {
+			if v == nil {
+				continue
+			}
+			if addonName == addon && k == key || property.Generate.SharesPool(schema[k].Generate) {
+				values = append(values, v)
+			}
+		}
+		return nil
+	}
+	collectCluster := func(envName, stageName string, c *Cluster) error {
+		err := collect(p.PropertyDefinitions(), c.PropertyLayers(p, envName, stageName), "")
+		if err != nil {
+			return fmt.Errorf("cluster %s/%s/%s: %w", envName, stageName, c.Name, err)
+		}
+		for addonName, tm := range p.ParsedAddons {
+			err := collect(tm.Properties, c.addonPropertyLayers(p, addonName, envName, stageName), addonName)
+			if err != nil {
+				return fmt.Errorf("cluster %s/%s/%s: addon %s: %w", envName, stageName, c.Name, addonName, err)
+			}
+		}
+		return nil
+	}
+
+	// the cluster may not have been added to the project yet
+	err := collectCluster(env, stage, cluster)
+	if err != nil {
+		return nil, err
+	}
+	for envName, e := range p.Environments {
+		for stageName, s := range e.Stages {
+			for _, c := range s.Clusters {
+				if c == cluster {
+					continue
+				}
+				err := collectCluster(envName, stageName, c)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return values, nil
+}
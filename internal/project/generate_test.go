@@ -0,0 +1,129 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/secret"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+)
+
+func TestProjectConfig_GenerateProperties(t *testing.T) {
+	pc := &ProjectConfig{
+		PropertySchema: map[string]PropertyDefinition{
+			"clusterID": {Property: template.Property{Type: template.PropertyTypeInt, Generate: &template.Generator{Strategy: template.GenerateStrategySequence}}},
+			"podCIDR":   {Property: template.Property{Type: template.PropertyTypeString, Generate: &template.Generator{Strategy: template.GenerateStrategyCIDR, Pool: "10.128.0.0/14", PrefixLength: 16}}},
+		},
+		Addons: map[string]Addon{
+			"network":  {DefaultEnabled: true},
+			"disabled": {},
+		},
+		ParsedAddons: map[string]template.TemplateManifest{
+			"network": {
+				Properties: map[string]template.Property{
+					"serviceCIDR": {Type: template.PropertyTypeString, Generate: &template.Generator{Strategy: template.GenerateStrategyCIDR, Pool: "10.128.0.0/14", PrefixLength: 16}},
+				},
+			},
+			"disabled": {
+				Properties: map[string]template.Property{
+					"token": {Type: template.PropertyTypeString, Generate: &template.Generator{Strategy: template.GenerateStrategyUUID}},
+				},
+			},
+		},
+		Environments: map[string]*Environment{
+			"dev": {
+				Stages: map[string]*Stage{
+					"test": {
+						Clusters: map[string]*Cluster{
+							"hugi": {
+								Properties: map[string]any{"clusterID": 1, "podCIDR": "10.128.0.0/16"},
+								Addons: map[string]*ClusterAddon{
+									"network": {Properties: map[string]any{"serviceCIDR": "10.129.0.0/16"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cluster := &Cluster{Name: "munin", Properties: map[string]any{"clusterID": 5}}
+	err := pc.GenerateProperties("dev", "test", cluster)
+	if err != nil {
+		t.Fatalf("ProjectConfig.GenerateProperties() error = %v", err)
+	}
+	want := &Cluster{
+		Name:       "munin",
+		Properties: map[string]any{"clusterID": 5, "podCIDR": "10.130.0.0/16"},
+		Addons: map[string]*ClusterAddon{
+			"network": {Properties: map[string]any{"serviceCIDR": "10.131.0.0/16"}},
+		},
+	}
+	if diff := cmp.Diff(want, cluster); diff != "" {
+		t.Errorf("ProjectConfig.GenerateProperties() mismatch (-want +got):\n%s", diff)
+	}
+
+	// generated values are kept
+	err = pc.GenerateProperties("dev", "test", cluster)
+	if err != nil {
+		t.Fatalf("ProjectConfig.GenerateProperties() error = %v", err)
+	}
+	if diff := cmp.Diff(want, cluster); diff != "" {
+		t.Errorf("ProjectConfig.GenerateProperties() mismatch (-want +got):\n%s", diff)
+	}
+
+	// the pool is exhausted
+	pc.SetCluster("dev", "test", cluster)
+	err = pc.GenerateProperties("dev", "test", &Cluster{Name: "odin"})
+	if err == nil {
+		t.Errorf("ProjectConfig.GenerateProperties() expected an error for an exhausted pool")
+	}
+}
+
+func TestProjectConfig_GenerateProperties_allocatedValues(t *testing.T) {
+	key, err := secret.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := key.Encrypt("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pc := &ProjectConfig{
+		PropertySchema: map[string]PropertyDefinition{
+			"clusterID": {Property: template.Property{Type: template.PropertyTypeInt, Generate: &template.Generator{Strategy: template.GenerateStrategySequence}}},
+			"token":     {Property: template.Property{Type: template.PropertyTypeSecret, Generate: &template.Generator{Strategy: template.GenerateStrategySequence}}},
+		},
+		Environments: map[string]*Environment{
+			"dev": {
+				// inherited by all clusters of the environment
+				Properties: map[string]any{"clusterID": 1},
+				Stages: map[string]*Stage{
+					"test": {
+						Clusters: map[string]*Cluster{
+							"hugi": {Properties: map[string]any{"token": token}},
+						},
+					},
+				},
+			},
+		},
+		secretKey: key,
+	}
+
+	cluster := &Cluster{Name: "munin"}
+	err = pc.GenerateProperties("dev", "test", cluster)
+	if err != nil {
+		t.Fatalf("ProjectConfig.GenerateProperties() error = %v", err)
+	}
+	if cluster.Properties["clusterID"] != 2 {
+		t.Errorf("ProjectConfig.GenerateProperties() clusterID = %v, want 2", cluster.Properties["clusterID"])
+	}
+	got, err := key.Decrypt(cluster.Properties["token"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "2" {
+		t.Errorf("ProjectConfig.GenerateProperties() token = %v, want 2", got)
+	}
+}
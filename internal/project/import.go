@@ -0,0 +1,295 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
+)
+
+// ImportResult describes the changes of an overlay import
+type ImportResult struct {
+	// Environments and Stages contain the created environments (<env>) and stages (<env>/<stage>)
+	Environments []string          `json:"environments"`
+	Stages       []string          `json:"stages"`
+	Clusters     []ImportedCluster `json:"clusters"`
+	// Unmapped contains the directories that could not be mapped to an environment, stage, cluster or addon
+	Unmapped []UnmappedDirectory `json:"unmapped"`
+	// Warnings contains problems of the imported clusters that must be fixed before they can be rendered
+	Warnings []string `json:"warnings"`
+}
+
+// ImportedCluster is a cluster that has been created or updated by an overlay import
+type ImportedCluster struct {
+	Environment string `json:"environment"`
+	Stage       string `json:"stage"`
+	Name        string `json:"name"`
+	Created     bool   `json:"created"`
+	// Addons contains the addons that have been found in the overlay of the cluster
+	Addons []string `json:"addons"`
+}
+
+// UnmappedDirectory is a directory of an overlay tree that has not been imported
+type UnmappedDirectory struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// ImportOverlays scans an overlay tree laid out as <env>/<stage>/<cluster>/<group>/<addon> and adds the environments, stages and clusters to the project
+// The addon directories are matched against the group and name of the addons of the project
+// The addons found in the overlay of a cluster are enabled for it, all other addons are disabled
+// Clusters are only imported if at least one addon directory has been found and the required properties are set
+// Directories that cannot be mapped are reported
+func (p *ProjectConfig) ImportOverlays(root string) (*ImportResult, error) {
+	result := &ImportResult{
+		Environments: []string{},
+		Stages:       []string{},
+		Clusters:     []ImportedCluster{},
+		Unmapped:     []UnmappedDirectory{},
+		Warnings:     []string{},
+	}
+	if p.Environments == nil {
+		p.Environments = map[string]*Environment{}
+	}
+
+	envDirs, err := subdirectories(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the overlays %s: %w", root, err)
+	}
+	for _, envName := range envDirs {
+		envPath := filepath.Join(root, envName)
+		if !p.HasEnvironment(envName) {
+			err := p.ValidateEnvironmentName(envName)
+			if err != nil {
+				result.unmapped(envPath, "invalid environment name: %v", err)
+				continue
+			}
+		}
+
+		stageDirs, err := subdirectories(envPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, stageName := range stageDirs {
+			stagePath := filepath.Join(envPath, stageName)
+			if !p.HasEnvironment(envName) || !p.GetEnvironment(envName).HasStage(stageName) {
+				err := p.ValidateStageName(stageName)
+				if err != nil {
+					result.unmapped(stagePath, "invalid stage name: %v", err)
+					continue
+				}
+			}
+
+			clusterDirs, err := subdirectories(stagePath)
+			if err != nil {
+				return nil, err
+			}
+			for _, clusterName := range clusterDirs {
+				err := p.importCluster(result, envName, stageName, clusterName, filepath.Join(stagePath, clusterName))
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// importCluster matches the addon directories of the cluster overlay and adds the cluster to the project
+func (p *ProjectConfig) importCluster(result *ImportResult, env, stage, name, clusterPath string) error {
+	exists := p.HasEnvironment(env) && p.GetEnvironment(env).HasStage(stage) && p.HasCluster(env, stage, name)
+	if !exists {
+		err := p.ValidateClusterName(env, stage, name)
+		if err != nil {
+			result.unmapped(clusterPath, "invalid cluster name: %v", err)
+			return nil
+		}
+	}
+
+	addonPaths := map[string]string{}
+	for _, addonName := range utils.SortStringSlice(utils.MapKeysToList(p.Addons)) {
+		addonPath := filepath.Join(clusterPath, filepath.FromSlash(p.Addons[addonName].Group), addonName)
+		info, err := os.Stat(addonPath)
+		if err == nil && info.IsDir() {
+			addonPaths[addonName] = addonPath
+		}
+	}
+	if len(addonPaths) == 0 {
+		result.unmapped(clusterPath, "no addon directories found")
+		return nil
+	}
+
+	// report all directories that are neither an addon nor one of its parents
+	err := filepath.WalkDir(clusterPath, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || path == clusterPath {
+			return nil
+		}
+		for _, addonPath := range addonPaths {
+			if path == addonPath {
+				return filepath.SkipDir
+			}
+			if strings.HasPrefix(addonPath, path+string(filepath.Separator)) {
+				return nil
+			}
+		}
+		result.unmapped(path, "does not match the group and name of an addon")
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan the cluster overlay %s: %w", clusterPath, err)
+	}
+
+	// the environment and stage are removed again if the cluster cannot be imported
+	createdEnv := !p.HasEnvironment(env)
+	if createdEnv {
+		p.Environments[env] = &Environment{
+			Name:       env,
+			Stages:     map[string]*Stage{},
+			Properties: map[string]any{},
+			Addons:     map[string]*ClusterAddon{},
+		}
+	}
+	createdStage := !p.GetEnvironment(env).HasStage(stage)
+	if createdStage {
+		if p.GetEnvironment(env).Stages == nil {
+			p.GetEnvironment(env).Stages = map[string]*Stage{}
+		}
+		p.GetEnvironment(env).Stages[stage] = &Stage{
+			Name:       stage,
+			Properties: map[string]any{},
+			Actions:    Actions{},
+			Clusters:   map[string]*Cluster{},
+			Addons:     map[string]*ClusterAddon{},
+		}
+	}
+
+	cluster := &Cluster{
+		Name:       name,
+		Addons:     map[string]*ClusterAddon{},
+		Properties: map[string]any{},
+	}
+	if exists {
+		cluster = p.GetCluster(env, stage, name)
+		if cluster.Name == "" {
+			cluster.Name = name
+		}
+		if cluster.Addons == nil {
+			cluster.Addons = map[string]*ClusterAddon{}
+		}
+	} else {
+		cluster.SetDefaultAddons(p)
+	}
+
+	// the overlay is the source of truth, so the enablement is only set explicitly if the inherited one differs
+	for _, addonName := range utils.SortStringSlice(utils.MapKeysToList(p.Addons)) {
+		_, found := addonPaths[addonName]
+		if found == cluster.AddonEnabled(p, addonName, env, stage) {
+			continue
+		}
+		if found {
+			cluster.EnableAddon(addonName)
+		} else {
+			cluster.DisableAddon(addonName)
+		}
+	}
+
+	err = p.GenerateProperties(env, stage, cluster)
+	if err != nil {
+		return fmt.Errorf("failed to generate the properties of cluster %s: %w", name, err)
+	}
+
+	// the properties are validated like ParseConfig does, so the saved project file can be loaded again
+	err = p.validateImportedProperties(env, stage, cluster)
+	if err != nil {
+		if createdStage {
+			delete(p.GetEnvironment(env).Stages, stage)
+		}
+		if createdEnv {
+			delete(p.Environments, env)
+		}
+		result.unmapped(clusterPath, "cannot be imported: %v", err)
+		return nil
+	}
+	if createdEnv {
+		result.Environments = append(result.Environments, env)
+	}
+	if createdStage {
+		result.Stages = append(result.Stages, env+"/"+stage)
+	}
+	p.SetCluster(env, stage, cluster)
+
+	imported := ImportedCluster{Environment: env, Stage: stage, Name: name, Created: !exists, Addons: utils.SortStringSlice(utils.MapKeysToList(addonPaths))}
+	result.Clusters = append(result.Clusters, imported)
+	result.checkCluster(p, env, stage, cluster)
+	return nil
+}
+
+// validateImportedProperties checks the properties of the environment, stage and cluster like ValidateProperties does
+func (p *ProjectConfig) validateImportedProperties(env, stage string, cluster *Cluster) error {
+	envProperties := p.GetEnvironment(env).Properties
+	stageProperties := p.GetStage(env, stage).Properties
+	err := p.CheckRequiredProperties(PropertyLevelEnvironment, p.PropertiesAt(envProperties))
+	if err != nil {
+		return fmt.Errorf("environment %s: %w", env, err)
+	}
+	err = p.CheckRequiredProperties(PropertyLevelStage, p.PropertiesAt(envProperties, stageProperties))
+	if err != nil {
+		return fmt.Errorf("stage %s/%s: %w", env, stage, err)
+	}
+	err = p.parseProperties(cluster.Properties)
+	if err != nil {
+		return fmt.Errorf("cluster %s/%s/%s: %w", env, stage, cluster.Name, err)
+	}
+	err = p.CheckRequiredProperties(PropertyLevelCluster, p.PropertiesAt(envProperties, stageProperties, cluster.Properties))
+	if err != nil {
+		return fmt.Errorf("cluster %s/%s/%s: %w", env, stage, cluster.Name, err)
+	}
+	return nil
+}
+
+// checkCluster adds warnings for missing required addon properties and policy violations of the imported cluster
+func (r *ImportResult) checkCluster(p *ProjectConfig, env, stage string, cluster *Cluster) {
+	ref := fmt.Sprintf("%s/%s/%s", env, stage, cluster.Name)
+	addons := cluster.AddonProperties(p, env, stage)
+	for _, addonName := range utils.SortStringSlice(utils.MapKeysToList(addons)) {
+		if !addons[addonName].IsEnabled() {
+			continue
+		}
+		err := addons[addonName].AllRequiredPropertiesSet(p, addonName)
+		if err != nil {
+			r.Warnings = append(r.Warnings, fmt.Sprintf("%s: addon %s: %v", ref, addonName, err))
+		}
+	}
+	violations, err := p.CheckClusterPolicies(env, stage, cluster)
+	if err != nil {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("%s: failed to check policies: %v", ref, err))
+		return
+	}
+	for _, v := range violations {
+		r.Warnings = append(r.Warnings, v.String())
+	}
+}
+
+func (r *ImportResult) unmapped(path, format string, args ...any) {
+	r.Unmapped = append(r.Unmapped, UnmappedDirectory{Path: path, Reason: fmt.Sprintf(format, args...)})
+}
+
+// subdirectories returns the sorted names of the directories in the given directory
+func subdirectories(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,169 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+)
+
+func TestProjectConfig_ImportOverlays(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{
+		"dev/dev/hugi/observability/monitoring",
+		"dev/dev/hugi/apps/debug/base",
+		"dev/dev/munin/apps/debug",
+		"dev/dev/munin/argocd",
+		"dev/qa/thor/misc",
+		"Prod/prod/odin/apps/debug",
+		"prod/prod/hugi/apps/debug",
+	} {
+		err := os.MkdirAll(filepath.Join(root, dir), 0775)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := &ProjectConfig{
+		Addons: map[string]Addon{
+			"monitoring": {Group: "observability", DefaultEnabled: true},
+			"debug":      {Group: "apps"},
+			"backup":     {Group: "apps", DefaultEnabled: true},
+		},
+		ParsedAddons: map[string]template.TemplateManifest{
+			"monitoring": {Name: "monitoring", Group: "observability", Properties: map[string]template.Property{
+				"retention": {Type: template.PropertyTypeString, Required: true},
+			}},
+			"debug":  {Name: "debug", Group: "apps"},
+			"backup": {Name: "backup", Group: "apps"},
+		},
+	}
+
+	got, err := config.ImportOverlays(root)
+	if err != nil {
+		t.Fatalf("ProjectConfig.ImportOverlays() error = %v", err)
+	}
+	want := &ImportResult{
+		Environments: []string{"dev"},
+		Stages:       []string{"dev/dev"},
+		Clusters: []ImportedCluster{
+			{Environment: "dev", Stage: "dev", Name: "hugi", Created: true, Addons: []string{"debug", "monitoring"}},
+			{Environment: "dev", Stage: "dev", Name: "munin", Created: true, Addons: []string{"debug"}},
+		},
+		Unmapped: []UnmappedDirectory{
+			{Path: filepath.Join(root, "Prod"), Reason: `invalid environment name: "Prod" must be a DNS-1123 label: lowercase alphanumeric characters or '-', starting and ending with an alphanumeric character and at most 63 characters`},
+			{Path: filepath.Join(root, "dev/dev/munin/argocd"), Reason: "does not match the group and name of an addon"},
+			{Path: filepath.Join(root, "dev/qa/thor"), Reason: "no addon directories found"},
+			{Path: filepath.Join(root, "prod/prod/hugi"), Reason: `invalid cluster name: "hugi" is already used by a cluster in dev/dev`},
+		},
+		Warnings: []string{
+			"dev/dev/hugi: addon monitoring: [monitoring] property for key retention is required",
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ProjectConfig.ImportOverlays() mismatch (-want +got):\n%s", diff)
+	}
+
+	wantAddons := map[string]map[string]*ClusterAddon{
+		"hugi": {
+			"monitoring": {Properties: map[string]any{"retention": nil}},
+			"debug":      {Enabled: boolPtr(true)},
+			"backup":     {Enabled: boolPtr(false), Properties: map[string]any{}},
+		},
+		"munin": {
+			"monitoring": {Enabled: boolPtr(false), Properties: map[string]any{"retention": nil}},
+			"debug":      {Enabled: boolPtr(true)},
+			"backup":     {Enabled: boolPtr(false), Properties: map[string]any{}},
+		},
+	}
+	for name, want := range wantAddons {
+		if diff := cmp.Diff(want, config.GetCluster("dev", "dev", name).Addons); diff != "" {
+			t.Errorf("addons of cluster %s mismatch (-want +got):\n%s", name, diff)
+		}
+	}
+	if config.GetEnvironment("dev").HasStage("qa") {
+		t.Errorf("stage dev/qa without clusters must not be created")
+	}
+}
+
+func TestProjectConfig_ImportOverlays_RequiredProperties(t *testing.T) {
+	dir := t.TempDir()
+	for _, path := range []string{
+		"overlays/dev/dev/hugi/apps/debug",
+		"overlays/dev/qa/munin/apps/debug",
+		"overlays/prod/prod/odin/apps/debug",
+		"addons/debug",
+	} {
+		err := os.MkdirAll(filepath.Join(dir, path), 0775)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := os.WriteFile(filepath.Join(dir, "addons/debug/manifest.yaml"), []byte("name: debug\ngroup: apps\n"), 0664)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(dir, "PROJECT.yaml")
+	err = os.WriteFile(configPath, []byte(`basePath: overlays/
+templateBasePath: templates/
+propertySchema:
+  region:
+    type: string
+    required: true
+    requiredAt: environment
+  tier:
+    type: string
+    required: true
+    requiredAt: stage
+addons:
+  debug:
+    group: apps
+    path: `+filepath.Join(dir, "addons/debug")+`
+environments:
+  dev:
+    properties:
+      region: eu
+    stages:
+      qa:
+        properties:
+          tier: gold
+`), 0664)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := ParseConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := config.ImportOverlays(filepath.Join(dir, "overlays"))
+	if err != nil {
+		t.Fatalf("ProjectConfig.ImportOverlays() error = %v", err)
+	}
+	want := []UnmappedDirectory{
+		{Path: filepath.Join(dir, "overlays/dev/dev/hugi"), Reason: "cannot be imported: stage dev/dev: property tier is required at the stage level"},
+		{Path: filepath.Join(dir, "overlays/prod/prod/odin"), Reason: "cannot be imported: environment prod: property region is required at the environment level"},
+	}
+	if diff := cmp.Diff(want, result.Unmapped); diff != "" {
+		t.Errorf("ProjectConfig.ImportOverlays() unmapped mismatch (-want +got):\n%s", diff)
+	}
+	wantClusters := []ImportedCluster{{Environment: "dev", Stage: "qa", Name: "munin", Created: true, Addons: []string{"debug"}}}
+	if diff := cmp.Diff(wantClusters, result.Clusters); diff != "" {
+		t.Errorf("ProjectConfig.ImportOverlays() clusters mismatch (-want +got):\n%s", diff)
+	}
+	if config.HasEnvironment("prod") || config.GetEnvironment("dev").HasStage("dev") {
+		t.Errorf("environments and stages of skipped clusters must be removed")
+	}
+
+	// the saved project file must be loadable again
+	err = UpdateOrCreateConfig(configPath, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ParseConfig(configPath)
+	if err != nil {
+		t.Errorf("ParseConfig() of the imported project error = %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	referencePrefix = "${"
+	referenceSuffix = "}"
+	// referenceEscape is replaced by the reference prefix without resolving the reference
+	referenceEscape = "$${"
+)
+
+// interpolationScope contains the names that can be referenced by property values
+// Empty names are not available in the scope, e.g. the cluster name while rendering an environment template
+type interpolationScope struct {
+	Environment string
+	Stage       string
+	Cluster     string
+}
+
+// interpolator resolves the references in property values
+// Supported references are ${env.name}, ${stage.name}, ${cluster.name}, ${properties.<key>[.<nested key>]} and ${envvar.<NAME>}
+type interpolator struct {
+	scope      interpolationScope
+	properties map[string]any
+	resolved   map[string]any
+	// resolving is the stack of properties that are currently resolved, used to detect cycles
+	resolving []string
+	lookupEnv func(string) (string, bool)
+}
+
+func newInterpolator(scope interpolationScope, properties map[string]any) *interpolator {
+	return &interpolator{
+		scope:      scope,
+		properties: properties,
+		resolved:   map[string]any{},
+		lookupEnv:  os.LookupEnv,
+	}
+}
+
+// interpolateProperties resolves the references in the given properties, references to other properties are resolved against the properties themselves
+func interpolateProperties(scope interpolationScope, properties map[string]any) (map[string]any, error) {
+	i := newInterpolator(scope, properties)
+	result := make(map[string]any, len(properties))
+	for key := range properties {
+		value, err := i.resolveProperty(key)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+// interpolateValues resolves the references in the given values, references to properties are resolved against the given (already resolved) properties
+func interpolateValues(scope interpolationScope, properties, values map[string]any) (map[string]any, error) {
+	i := newInterpolator(scope, properties)
+	// the properties must not be resolved a second time
+	i.resolved = properties
+	result := make(map[string]any, len(values))
+	for key, value := range values {
+		interpolated, err := i.interpolate(value)
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %w", key, err)
+		}
+		result[key] = interpolated
+	}
+	return result, nil
+}
+
+// resolveProperty resolves the references of the property with the given key
+func (i *interpolator) resolveProperty(key string) (any, error) {
+	if value, ok := i.resolved[key]; ok {
+		return value, nil
+	}
+	for idx, resolving := range i.resolving {
+		if resolving == key {
+			cycle := append(append([]string{}, i.resolving[idx:]...), key)
+			return nil, fmt.Errorf("reference cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	value, ok := i.properties[key]
+	if !ok {
+		return nil, fmt.Errorf("property %s is not defined", key)
+	}
+
+	i.resolving = append(i.resolving, key)
+	defer func() {
+		i.resolving = i.resolving[:len(i.resolving)-1]
+	}()
+
+	interpolated, err := i.interpolate(value)
+	if err != nil {
+		if len(i.resolving) > 1 {
+			// the error is reported by the outermost property
+			return nil, err
+		}
+		return nil, fmt.Errorf("property %s: %w", key, err)
+	}
+	i.resolved[key] = interpolated
+	return interpolated, nil
+}
+
+// interpolate resolves the references in all strings of the given value
+func (i *interpolator) interpolate(value any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return i.interpolateString(v)
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			interpolated, err := i.interpolate(item)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = interpolated
+		}
+		return result, nil
+	case []any:
+		result := make([]any, 0, len(v))
+		for _, item := range v {
+			interpolated, err := i.interpolate(item)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, interpolated)
+		}
+		return result, nil
+	default:
+		return value, nil
+	}
+}
+
+// interpolateString resolves the references in the given string
+// If the string consists of a single reference, the referenced value is returned as it is, so that lists, maps and numbers keep their type
+func (i *interpolator) interpolateString(s string) (any, error) {
+	if !strings.Contains(s, referencePrefix) {
+		return s, nil
+	}
+	if strings.HasPrefix(s, referencePrefix) && strings.Index(s, referenceSuffix) == len(s)-1 {
+		return i.lookup(s[len(referencePrefix) : len(s)-1])
+	}
+
+	sb := strings.Builder{}
+	for len(s) > 0 {
+		if strings.HasPrefix(s, referenceEscape) {
+			sb.WriteString(referencePrefix)
+			s = s[len(referenceEscape):]
+			continue
+		}
+		if !strings.HasPrefix(s, referencePrefix) {
+			sb.WriteByte(s[0])
+			s = s[1:]
+			continue
+		}
+		end := strings.Index(s, referenceSuffix)
+		if end == -1 {
+			return nil, fmt.Errorf("unterminated reference %q", s)
+		}
+		value, err := i.lookup(s[len(referencePrefix):end])
+		if err != nil {
+			return nil, err
+		}
+		sb.WriteString(fmt.Sprint(value))
+		s = s[end+len(referenceSuffix):]
+	}
+	return sb.String(), nil
+}
+
+// lookup returns the value of the given reference
+func (i *interpolator) lookup(reference string) (any, error) {
+	root, path, _ := strings.Cut(reference, ".")
+	switch root {
+	case "env", "stage", "cluster":
+		if path != "name" {
+			return nil, fmt.Errorf("unknown reference ${%s}, only ${%s.name} is supported", reference, root)
+		}
+		name := map[string]string{
+			"env":     i.scope.Environment,
+			"stage":   i.scope.Stage,
+			"cluster": i.scope.Cluster,
+		}[root]
+		if name == "" {
+			return nil, fmt.Errorf("reference ${%s} is not available in this scope", reference)
+		}
+		return name, nil
+	case "envvar":
+		value, ok := i.lookupEnv(path)
+		if !ok {
+			return nil, fmt.Errorf("environment variable %s referenced by ${%s} is not set", path, reference)
+		}
+		return value, nil
+	case "properties":
+		keys := strings.Split(path, ".")
+		value, err := i.resolveProperty(keys[0])
+		if err != nil {
+			return nil, err
+		}
+		for idx, key := range keys[1:] {
+			m, ok := value.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("reference ${%s} is invalid, %s is not a map", reference, strings.Join(keys[:idx+1], "."))
+			}
+			value, ok = m[key]
+			if !ok {
+				return nil, fmt.Errorf("reference ${%s} is invalid, key %s is not defined", reference, key)
+			}
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("unknown reference ${%s}", reference)
+	}
+}
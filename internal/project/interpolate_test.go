@@ -0,0 +1,165 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_interpolateProperties(t *testing.T) {
+	scope := interpolationScope{
+		Environment: "dev",
+		Stage:       "test",
+		Cluster:     "hugi",
+	}
+	tests := []struct {
+		name       string
+		scope      interpolationScope
+		properties map[string]any
+		want       map[string]any
+		wantErr    bool
+	}{
+		{
+			name:  "names",
+			scope: scope,
+			properties: map[string]any{
+				"host": "${cluster.name}.${stage.name}.${env.name}.example.com",
+			},
+			want: map[string]any{
+				"host": "hugi.test.dev.example.com",
+			},
+		},
+		{
+			name:  "property references",
+			scope: scope,
+			properties: map[string]any{
+				"baseDomain": "${env.name}.example.com",
+				"apiURL":     "https://api.${properties.baseDomain}",
+				"hosts":      []any{"${properties.apiURL}"},
+			},
+			want: map[string]any{
+				"baseDomain": "dev.example.com",
+				"apiURL":     "https://api.dev.example.com",
+				"hosts":      []any{"https://api.dev.example.com"},
+			},
+		},
+		{
+			name:  "single reference keeps the type",
+			scope: scope,
+			properties: map[string]any{
+				"resources": map[string]any{"limits": map[string]any{"cpu": 2}},
+				"cpu":       "${properties.resources.limits.cpu}",
+				"limits":    "${properties.resources.limits}",
+			},
+			want: map[string]any{
+				"resources": map[string]any{"limits": map[string]any{"cpu": 2}},
+				"cpu":       2,
+				"limits":    map[string]any{"cpu": 2},
+			},
+		},
+		{
+			name:  "environment variables",
+			scope: scope,
+			properties: map[string]any{
+				"token": "Bearer ${envvar.OGC_TEST_TOKEN}",
+			},
+			want: map[string]any{
+				"token": "Bearer secret",
+			},
+		},
+		{
+			name:  "escaped reference",
+			scope: scope,
+			properties: map[string]any{
+				"script": "echo $${HOME} ${cluster.name}",
+			},
+			want: map[string]any{
+				"script": "echo ${HOME} hugi",
+			},
+		},
+		{
+			name:  "cycle",
+			scope: scope,
+			properties: map[string]any{
+				"a": "${properties.b}",
+				"b": "x-${properties.a}",
+			},
+			wantErr: true,
+		},
+		{
+			name:  "self reference",
+			scope: scope,
+			properties: map[string]any{
+				"a": "${properties.a}",
+			},
+			wantErr: true,
+		},
+		{
+			name:  "unknown property",
+			scope: scope,
+			properties: map[string]any{
+				"a": "${properties.b}",
+			},
+			wantErr: true,
+		},
+		{
+			name:  "missing environment variable",
+			scope: scope,
+			properties: map[string]any{
+				"a": "${envvar.OGC_TEST_MISSING}",
+			},
+			wantErr: true,
+		},
+		{
+			name:  "cluster not in scope",
+			scope: interpolationScope{Environment: "dev"},
+			properties: map[string]any{
+				"a": "${cluster.name}",
+			},
+			wantErr: true,
+		},
+		{
+			name:  "unterminated reference",
+			scope: scope,
+			properties: map[string]any{
+				"a": "prefix-${cluster.name",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OGC_TEST_TOKEN", "secret")
+			got, err := interpolateProperties(tt.scope, tt.properties)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("interpolateProperties() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("interpolateProperties() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_interpolateValues(t *testing.T) {
+	properties := map[string]any{
+		"baseDomain": "dev.example.com",
+		"literal":    "${not-resolved-again}",
+	}
+	values := map[string]any{
+		"ingress": map[string]any{"host": "grafana.${properties.baseDomain}"},
+		"literal": "${properties.literal}",
+	}
+	want := map[string]any{
+		"ingress": map[string]any{"host": "grafana.dev.example.com"},
+		"literal": "${not-resolved-again}",
+	}
+	got, err := interpolateValues(interpolationScope{Environment: "dev"}, properties, values)
+	if err != nil {
+		t.Fatalf("interpolateValues() error = %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("interpolateValues() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,185 @@
+package project
+
+import (
+	"slices"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
+)
+
+// InventoryFilter selects the entities of the inventory, empty fields match everything
+type InventoryFilter struct {
+	Environment string
+	Stage       string
+	// AddonEnabled selects the clusters the addon is enabled for
+	AddonEnabled string
+	// Labels selects the clusters that have all labels, an empty value only requires the label to exist
+	Labels map[string]string
+}
+
+// EnvironmentInfo is an environment of the inventory
+type EnvironmentInfo struct {
+	Name     string `json:"name"`
+	Stages   int    `json:"stages"`
+	Clusters int    `json:"clusters"`
+}
+
+// StageInfo is a stage of the inventory
+type StageInfo struct {
+	Environment string `json:"environment"`
+	Name        string `json:"name"`
+	Clusters    int    `json:"clusters"`
+}
+
+// ClusterInfo is a cluster of the inventory together with its resolved addon enablement
+type ClusterInfo struct {
+	Environment string            `json:"environment"`
+	Stage       string            `json:"stage"`
+	Name        string            `json:"name"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	// Addons contains the names of the enabled addons
+	Addons []string `json:"addons"`
+}
+
+// AddonInfo is an addon of the inventory
+type AddonInfo struct {
+	Name           string `json:"name"`
+	Group          string `json:"group"`
+	Path           string `json:"path"`
+	DefaultEnabled bool   `json:"defaultEnabled"`
+	// Clusters is the number of selected clusters the addon is enabled for
+	Clusters int `json:"clusters"`
+}
+
+// hasClusterFilter checks if the filter selects clusters by their addons or labels
+func (f InventoryFilter) hasClusterFilter() bool {
+	return f.AddonEnabled != "" || len(f.Labels) > 0
+}
+
+// matchesCluster checks if the cluster is selected by the addon and label filters
+func (f InventoryFilter) matchesCluster(config *ProjectConfig, env, stage string, c *Cluster) bool {
+	if f.AddonEnabled != "" && !c.AddonEnabled(config, f.AddonEnabled, env, stage) {
+		return false
+	}
+	for key, value := range f.Labels {
+		got, ok := c.Labels[key]
+		if !ok || (value != "" && got != value) {
+			return false
+		}
+	}
+	return true
+}
+
+// ListClusters returns the clusters selected by the filter sorted by environment, stage and name
+func (p *ProjectConfig) ListClusters(filter InventoryFilter) []ClusterInfo {
+	clusters := []ClusterInfo{}
+	for _, envName := range utils.SortStringSlice(utils.MapKeysToList(p.Environments)) {
+		if filter.Environment != "" && filter.Environment != envName {
+			continue
+		}
+		env := p.Environments[envName]
+		for _, stageName := range utils.SortStringSlice(utils.MapKeysToList(env.Stages)) {
+			if filter.Stage != "" && filter.Stage != stageName {
+				continue
+			}
+			stage := env.Stages[stageName]
+			for _, clusterName := range utils.SortStringSlice(utils.MapKeysToList(stage.Clusters)) {
+				cluster := stage.Clusters[clusterName]
+				if !filter.matchesCluster(p, envName, stageName, cluster) {
+					continue
+				}
+				addons := []string{}
+				for _, addon := range utils.SortStringSlice(utils.MapKeysToList(p.Addons)) {
+					if cluster.AddonEnabled(p, addon, envName, stageName) {
+						addons = append(addons, addon)
+					}
+				}
+				clusters = append(clusters, ClusterInfo{
+					Environment: envName,
+					Stage:       stageName,
+					Name:        clusterName,
+					Labels:      cluster.Labels,
+					Addons:      addons,
+				})
+			}
+		}
+	}
+	return clusters
+}
+
+// ListStages returns the stages selected by the filter sorted by environment and name
+// If the filter selects clusters by their addons or labels, only stages with selected clusters are returned
+func (p *ProjectConfig) ListStages(filter InventoryFilter) []StageInfo {
+	clusters := p.ListClusters(filter)
+	stages := []StageInfo{}
+	for _, envName := range utils.SortStringSlice(utils.MapKeysToList(p.Environments)) {
+		if filter.Environment != "" && filter.Environment != envName {
+			continue
+		}
+		for _, stageName := range utils.SortStringSlice(utils.MapKeysToList(p.Environments[envName].Stages)) {
+			if filter.Stage != "" && filter.Stage != stageName {
+				continue
+			}
+			info := StageInfo{Environment: envName, Name: stageName}
+			for _, c := range clusters {
+				if c.Environment == envName && c.Stage == stageName {
+					info.Clusters++
+				}
+			}
+			if filter.hasClusterFilter() && info.Clusters == 0 {
+				continue
+			}
+			stages = append(stages, info)
+		}
+	}
+	return stages
+}
+
+// ListEnvironments returns the environments selected by the filter sorted by name
+// If the filter selects clusters by their addons or labels, only environments with selected clusters are returned
+func (p *ProjectConfig) ListEnvironments(filter InventoryFilter) []EnvironmentInfo {
+	stages := p.ListStages(filter)
+	environments := []EnvironmentInfo{}
+	for _, envName := range utils.SortStringSlice(utils.MapKeysToList(p.Environments)) {
+		if filter.Environment != "" && filter.Environment != envName {
+			continue
+		}
+		info := EnvironmentInfo{Name: envName}
+		for _, s := range stages {
+			if s.Environment == envName {
+				info.Stages++
+				info.Clusters += s.Clusters
+			}
+		}
+		if (filter.Stage != "" || filter.hasClusterFilter()) && info.Stages == 0 {
+			continue
+		}
+		environments = append(environments, info)
+	}
+	return environments
+}
+
+// ListAddons returns the addons of the project sorted by name
+// The clusters are counted for the clusters selected by the filter, the addon filter selects the addon itself
+func (p *ProjectConfig) ListAddons(filter InventoryFilter) []AddonInfo {
+	clusters := p.ListClusters(filter)
+	addons := []AddonInfo{}
+	for _, name := range utils.SortStringSlice(utils.MapKeysToList(p.Addons)) {
+		if filter.AddonEnabled != "" && filter.AddonEnabled != name {
+			continue
+		}
+		addon := p.Addons[name]
+		info := AddonInfo{
+			Name:           name,
+			Group:          addon.Group,
+			Path:           addon.Path,
+			DefaultEnabled: addon.DefaultEnabled,
+		}
+		for _, c := range clusters {
+			if slices.Contains(c.Addons, name) {
+				info.Clusters++
+			}
+		}
+		addons = append(addons, info)
+	}
+	return addons
+}
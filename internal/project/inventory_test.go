@@ -0,0 +1,194 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func newInventoryConfig() *ProjectConfig {
+	return &ProjectConfig{
+		Addons: map[string]Addon{
+			"monitoring": {Group: "observability", Path: "addons/monitoring", DefaultEnabled: true},
+			"debug":      {Group: "apps", Path: "addons/debug"},
+		},
+		Environments: map[string]*Environment{
+			"dev": {Stages: map[string]*Stage{
+				"dev": {
+					Addons: map[string]*ClusterAddon{"debug": {Enabled: boolPtr(true)}},
+					Clusters: map[string]*Cluster{
+						"hugi": {Labels: map[string]string{"region": "eu"}},
+						"munin": {
+							Labels: map[string]string{"region": "us"},
+							Addons: map[string]*ClusterAddon{"monitoring": {Enabled: boolPtr(false)}},
+						},
+					},
+				},
+				"qa": {},
+			}},
+			"prod": {Stages: map[string]*Stage{
+				"prod": {Clusters: map[string]*Cluster{
+					"odin": {Labels: map[string]string{"region": "eu", "tier": "gold"}},
+				}},
+			}},
+		},
+	}
+}
+
+func TestProjectConfig_ListClusters(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter InventoryFilter
+		want   []ClusterInfo
+	}{
+		{
+			name:   "all clusters",
+			filter: InventoryFilter{},
+			want: []ClusterInfo{
+				{Environment: "dev", Stage: "dev", Name: "hugi", Labels: map[string]string{"region": "eu"}, Addons: []string{"debug", "monitoring"}},
+				{Environment: "dev", Stage: "dev", Name: "munin", Labels: map[string]string{"region": "us"}, Addons: []string{"debug"}},
+				{Environment: "prod", Stage: "prod", Name: "odin", Labels: map[string]string{"region": "eu", "tier": "gold"}, Addons: []string{"monitoring"}},
+			},
+		},
+		{
+			name:   "environment",
+			filter: InventoryFilter{Environment: "prod"},
+			want: []ClusterInfo{
+				{Environment: "prod", Stage: "prod", Name: "odin", Labels: map[string]string{"region": "eu", "tier": "gold"}, Addons: []string{"monitoring"}},
+			},
+		},
+		{
+			name:   "addon enabled",
+			filter: InventoryFilter{AddonEnabled: "monitoring"},
+			want: []ClusterInfo{
+				{Environment: "dev", Stage: "dev", Name: "hugi", Labels: map[string]string{"region": "eu"}, Addons: []string{"debug", "monitoring"}},
+				{Environment: "prod", Stage: "prod", Name: "odin", Labels: map[string]string{"region": "eu", "tier": "gold"}, Addons: []string{"monitoring"}},
+			},
+		},
+		{
+			name:   "labels",
+			filter: InventoryFilter{Labels: map[string]string{"region": "eu", "tier": ""}},
+			want: []ClusterInfo{
+				{Environment: "prod", Stage: "prod", Name: "odin", Labels: map[string]string{"region": "eu", "tier": "gold"}, Addons: []string{"monitoring"}},
+			},
+		},
+		{
+			name:   "no match",
+			filter: InventoryFilter{Stage: "qa"},
+			want:   []ClusterInfo{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newInventoryConfig().ListClusters(tt.filter)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ProjectConfig.ListClusters() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProjectConfig_ListStages(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter InventoryFilter
+		want   []StageInfo
+	}{
+		{
+			name:   "all stages",
+			filter: InventoryFilter{},
+			want: []StageInfo{
+				{Environment: "dev", Name: "dev", Clusters: 2},
+				{Environment: "dev", Name: "qa", Clusters: 0},
+				{Environment: "prod", Name: "prod", Clusters: 1},
+			},
+		},
+		{
+			name:   "only stages with selected clusters",
+			filter: InventoryFilter{Labels: map[string]string{"region": "us"}},
+			want: []StageInfo{
+				{Environment: "dev", Name: "dev", Clusters: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newInventoryConfig().ListStages(tt.filter)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ProjectConfig.ListStages() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProjectConfig_ListEnvironments(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter InventoryFilter
+		want   []EnvironmentInfo
+	}{
+		{
+			name:   "all environments",
+			filter: InventoryFilter{},
+			want: []EnvironmentInfo{
+				{Name: "dev", Stages: 2, Clusters: 2},
+				{Name: "prod", Stages: 1, Clusters: 1},
+			},
+		},
+		{
+			name:   "stage",
+			filter: InventoryFilter{Stage: "qa"},
+			want: []EnvironmentInfo{
+				{Name: "dev", Stages: 1, Clusters: 0},
+			},
+		},
+		{
+			name:   "addon enabled",
+			filter: InventoryFilter{AddonEnabled: "debug"},
+			want: []EnvironmentInfo{
+				{Name: "dev", Stages: 1, Clusters: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newInventoryConfig().ListEnvironments(tt.filter)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ProjectConfig.ListEnvironments() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProjectConfig_ListAddons(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter InventoryFilter
+		want   []AddonInfo
+	}{
+		{
+			name:   "all addons",
+			filter: InventoryFilter{},
+			want: []AddonInfo{
+				{Name: "debug", Group: "apps", Path: "addons/debug", Clusters: 2},
+				{Name: "monitoring", Group: "observability", Path: "addons/monitoring", DefaultEnabled: true, Clusters: 2},
+			},
+		},
+		{
+			name:   "clusters of the environment",
+			filter: InventoryFilter{Environment: "prod"},
+			want: []AddonInfo{
+				{Name: "debug", Group: "apps", Path: "addons/debug", Clusters: 0},
+				{Name: "monitoring", Group: "observability", Path: "addons/monitoring", DefaultEnabled: true, Clusters: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newInventoryConfig().ListAddons(tt.filter)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ProjectConfig.ListAddons() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
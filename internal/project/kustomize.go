@@ -0,0 +1,138 @@
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
+)
+
+// KustomizeBuild configures the verification of the rendered cluster directories with kustomize build --enable-helm
+type KustomizeBuild struct {
+	Enabled bool `json:"enabled"`
+	// Command is the kustomize binary, defaults to kustomize
+	Command string `json:"command,omitempty"`
+	// HelmCommand is the helm binary used by kustomize to inflate the charts
+	HelmCommand string `json:"helmCommand,omitempty"`
+	// Args are additional arguments of kustomize build, e.g. --load-restrictor=LoadRestrictionsNone
+	Args []string `json:"args,omitempty"`
+	// OutputPath is the directory the hydrated output is stored in for review, nothing is stored if it is empty
+	OutputPath string `json:"outputPath,omitempty"`
+}
+
+// IsEnabled checks if the verification is configured and enabled
+func (k *KustomizeBuild) IsEnabled() bool {
+	return k != nil && k.Enabled
+}
+
+// command returns the kustomize command and arguments to build the given directory
+func (k *KustomizeBuild) command(dir string) (string, []string) {
+	command := k.Command
+	if command == "" {
+		command = "kustomize"
+	}
+	args := []string{"build", "--enable-helm"}
+	if k.HelmCommand != "" {
+		args = append(args, "--helm-command", k.HelmCommand)
+	}
+	args = append(args, k.Args...)
+	return command, append(args, dir)
+}
+
+// KustomizeBuildResult is the result of kustomize build for a rendered template or addon directory
+type KustomizeBuildResult struct {
+	// Name is the name of the template or addon
+	Name  string
+	Addon bool
+	// Path is the rendered directory
+	Path string
+	// OutputPath is the location of the hydrated output, it is empty if the output is not stored
+	OutputPath string
+	Err        error
+}
+
+// VerifyKustomizeBuild runs kustomize build on all rendered template and addon directories of the cluster that contain a kustomization
+// Failures are captured per template and addon, an error is only returned if the directories could not be determined
+func (c *Cluster) VerifyKustomizeBuild(config *ProjectConfig, env, stage string) ([]KustomizeBuildResult, error) {
+	if !config.KustomizeBuild.IsEnabled() {
+		return nil, nil
+	}
+	clusterPath := path.Join(config.BasePath, env, stage, c.Name)
+
+	templates, err := template.LoadTemplateManifest(config.TemplateBasePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load base templates: %w", err)
+	}
+	targets := []KustomizeBuildResult{}
+	for _, t := range templates {
+		if t.TemplateManifest.GetScope() != template.TemplateScopeCluster || !c.IsTemplateEnabled(config, env, stage, t.TemplateManifest) {
+			continue
+		}
+		targets = append(targets, KustomizeBuildResult{Name: t.TemplateManifest.Name, Path: t.TemplateManifest.Name})
+	}
+	for _, addonName := range utils.SortStringSlice(utils.MapKeysToList(config.ParsedAddons)) {
+		if !c.AddonEnabled(config, addonName, env, stage) {
+			continue
+		}
+		addon := config.ParsedAddons[addonName]
+		targets = append(targets, KustomizeBuildResult{Name: addonName, Addon: true, Path: path.Join(addon.Group, addon.Name)})
+	}
+
+	results := []KustomizeBuildResult{}
+	for _, target := range targets {
+		relPath := target.Path
+		target.Path = path.Join(clusterPath, relPath)
+		if !hasKustomization(target.Path) {
+			continue
+		}
+		output, err := config.KustomizeBuild.build(target.Path)
+		if err != nil {
+			target.Err = err
+			results = append(results, target)
+			continue
+		}
+		if config.KustomizeBuild.OutputPath != "" {
+			target.OutputPath = path.Join(config.KustomizeBuild.OutputPath, env, stage, c.Name, relPath+".yaml")
+			target.Err = writeHydratedOutput(target.OutputPath, output)
+		}
+		results = append(results, target)
+	}
+	return results, nil
+}
+
+// build runs kustomize build on the directory and returns the hydrated output
+func (k *KustomizeBuild) build(dir string) ([]byte, error) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	command, args := k.command(dir)
+	err := utils.ExecuteShellCommand(stdout, stderr, command, args...)
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// writeHydratedOutput stores the output of kustomize build at the given location
+func writeHydratedOutput(fpath string, output []byte) error {
+	err := os.MkdirAll(path.Dir(fpath), 0755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fpath, output, 0644)
+}
+
+// hasKustomization checks if the directory contains a kustomization file
+func hasKustomization(dir string) bool {
+	for _, name := range []string{"kustomization.yaml", "kustomization.yml", "Kustomization"} {
+		if _, err := os.Stat(path.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+)
+
+// fakeKustomize prints the built directory and fails for directories containing a file named broken
+const fakeKustomize = `#!/bin/sh
+if [ -f "$3/broken" ]; then
+  echo "accumulating resources: broken" >&2
+  exit 1
+fi
+echo "built: $3"
+`
+
+func TestCluster_VerifyKustomizeBuild(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"kustomize":                                         fakeKustomize,
+		"templates/base/manifest.yaml":                      "name: base\nfiles:\n  - kustomization.yaml\n",
+		"templates/other/manifest.yaml":                     "name: other\nfiles:\n  - values.yaml\n",
+		"out/dev/dev/c1/base/kustomization.yaml":            "",
+		"out/dev/dev/c1/other/values.yaml":                  "",
+		"out/dev/dev/c1/apps/monitoring/kustomization.yaml": "",
+		"out/dev/dev/c1/apps/logging/kustomization.yaml":    "",
+		"out/dev/dev/c1/apps/logging/broken":                "",
+		"out/dev/dev/c1/apps/disabled/kustomization.yaml":   "",
+	}
+	for name, content := range files {
+		fpath := filepath.Join(dir, name)
+		err := os.MkdirAll(filepath.Dir(fpath), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(fpath, []byte(content), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := func(kb *KustomizeBuild) *ProjectConfig {
+		return &ProjectConfig{
+			BasePath:         filepath.Join(dir, "out"),
+			TemplateBasePath: filepath.Join(dir, "templates"),
+			KustomizeBuild:   kb,
+			Addons: map[string]Addon{
+				"monitoring": {DefaultEnabled: true},
+				"logging":    {DefaultEnabled: true},
+				"disabled":   {DefaultEnabled: false},
+			},
+			ParsedAddons: map[string]template.TemplateManifest{
+				"monitoring": {Name: "monitoring", Group: "apps"},
+				"logging":    {Name: "logging", Group: "apps"},
+				"disabled":   {Name: "disabled", Group: "apps"},
+			},
+			Environments: map[string]*Environment{
+				"dev": {Stages: map[string]*Stage{"dev": {}}},
+			},
+		}
+	}
+	clusterPath := filepath.Join(dir, "out", "dev", "dev", "c1")
+
+	tests := []struct {
+		name       string
+		build      *KustomizeBuild
+		want       []KustomizeBuildResult
+		wantErrs   []string
+		wantOutput map[string]string
+	}{
+		{
+			name:  "not configured",
+			build: nil,
+		},
+		{
+			name:  "disabled",
+			build: &KustomizeBuild{Command: filepath.Join(dir, "kustomize")},
+		},
+		{
+			name:  "without output path",
+			build: &KustomizeBuild{Enabled: true, Command: filepath.Join(dir, "kustomize")},
+			want: []KustomizeBuildResult{
+				{Name: "base", Path: filepath.Join(clusterPath, "base")},
+				{Name: "logging", Addon: true, Path: filepath.Join(clusterPath, "apps", "logging")},
+				{Name: "monitoring", Addon: true, Path: filepath.Join(clusterPath, "apps", "monitoring")},
+			},
+			wantErrs: []string{"", "exit status 1: accumulating resources: broken", ""},
+		},
+		{
+			name:  "with output path",
+			build: &KustomizeBuild{Enabled: true, Command: filepath.Join(dir, "kustomize"), OutputPath: filepath.Join(dir, "hydrated")},
+			want: []KustomizeBuildResult{
+				{Name: "base", Path: filepath.Join(clusterPath, "base"), OutputPath: filepath.Join(dir, "hydrated", "dev", "dev", "c1", "base.yaml")},
+				{Name: "logging", Addon: true, Path: filepath.Join(clusterPath, "apps", "logging")},
+				{Name: "monitoring", Addon: true, Path: filepath.Join(clusterPath, "apps", "monitoring"), OutputPath: filepath.Join(dir, "hydrated", "dev", "dev", "c1", "apps", "monitoring.yaml")},
+			},
+			wantErrs: []string{"", "exit status 1: accumulating resources: broken", ""},
+			wantOutput: map[string]string{
+				filepath.Join(dir, "hydrated", "dev", "dev", "c1", "base.yaml"):               "built: " + filepath.Join(clusterPath, "base") + "\n",
+				filepath.Join(dir, "hydrated", "dev", "dev", "c1", "apps", "monitoring.yaml"): "built: " + filepath.Join(clusterPath, "apps", "monitoring") + "\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Cluster{Name: "c1"}
+			got, err := c.VerifyKustomizeBuild(config(tt.build), "dev", "dev")
+			if err != nil {
+				t.Fatalf("Cluster.VerifyKustomizeBuild() error = %v", err)
+			}
+			gotErrs := []string{}
+			for idx := range got {
+				msg := ""
+				if got[idx].Err != nil {
+					msg = got[idx].Err.Error()
+				}
+				gotErrs = append(gotErrs, msg)
+				got[idx].Err = nil
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Cluster.VerifyKustomizeBuild() mismatch (-want +got):\n%s", diff)
+			}
+			if len(tt.wantErrs) > 0 {
+				if diff := cmp.Diff(tt.wantErrs, gotErrs); diff != "" {
+					t.Errorf("Cluster.VerifyKustomizeBuild() errors mismatch (-want +got):\n%s", diff)
+				}
+			}
+			for fpath, want := range tt.wantOutput {
+				bts, err := os.ReadFile(fpath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(want, string(bts)); diff != "" {
+					t.Errorf("hydrated output %s mismatch (-want +got):\n%s", fpath, diff)
+				}
+			}
+		})
+	}
+}
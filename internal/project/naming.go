@@ -0,0 +1,184 @@
+package project
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
+)
+
+const dns1123LabelMaxLength = 63
+
+var dns1123Label = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// NamingPolicy restricts the names of the environments, stages and clusters of the project
+// Names are always required to be DNS-1123 labels, because they are used as directory and ArgoCD application names
+type NamingPolicy struct {
+	Environments *NamingRule `json:"environments,omitempty"`
+	Stages       *NamingRule `json:"stages,omitempty"`
+	Clusters     *NamingRule `json:"clusters,omitempty"`
+}
+
+// NamingRule defines additional restrictions for names
+type NamingRule struct {
+	// Pattern is a regular expression the name must match
+	Pattern string `json:"pattern,omitempty"`
+	// MaxLength is the maximum length of the name, it can only lower the DNS-1123 limit of 63 characters
+	MaxLength int `json:"maxLength,omitempty"`
+	// Reserved are names that must not be used
+	Reserved []string `json:"reserved,omitempty"`
+
+	// compiled caches the compiled pattern
+	compiled *regexp.Regexp
+}
+
+// Validate checks that the name is a DNS-1123 label and complies with the rule, the rule may be nil
+func (r *NamingRule) Validate(name string) error {
+	if len(name) > dns1123LabelMaxLength || !dns1123Label.MatchString(name) {
+		return fmt.Errorf("%q must be a DNS-1123 label: lowercase alphanumeric characters or '-', starting and ending with an alphanumeric character and at most %d characters", name, dns1123LabelMaxLength)
+	}
+	if r == nil {
+		return nil
+	}
+	if r.MaxLength > 0 && len(name) > r.MaxLength {
+		return fmt.Errorf("%q must be at most %d characters", name, r.MaxLength)
+	}
+	if slices.Contains(r.Reserved, name) {
+		return fmt.Errorf("%q is a reserved name", name)
+	}
+	if r.Pattern != "" {
+		pattern, err := r.pattern()
+		if err != nil {
+			return err
+		}
+		if !pattern.MatchString(name) {
+			return fmt.Errorf("%q does not match the naming pattern %s", name, r.Pattern)
+		}
+	}
+	return nil
+}
+
+// validatePattern checks that the pattern of the rule is a valid regular expression
+func (r *NamingRule) validatePattern() error {
+	if r == nil || r.Pattern == "" {
+		return nil
+	}
+	_, err := r.pattern()
+	return err
+}
+
+// pattern returns the compiled pattern of the rule, it is only compiled again if the pattern has been changed
+func (r *NamingRule) pattern() (*regexp.Regexp, error) {
+	if r.compiled != nil && r.compiled.String() == r.Pattern {
+		return r.compiled, nil
+	}
+	compiled, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid naming pattern %q: %w", r.Pattern, err)
+	}
+	r.compiled = compiled
+	return compiled, nil
+}
+
+// environments returns the naming rule of the environments, nil is returned if none has been defined
+func (n *NamingPolicy) environments() *NamingRule {
+	if n == nil {
+		return nil
+	}
+	return n.Environments
+}
+
+// stages returns the naming rule of the stages, nil is returned if none has been defined
+func (n *NamingPolicy) stages() *NamingRule {
+	if n == nil {
+		return nil
+	}
+	return n.Stages
+}
+
+// clusters returns the naming rule of the clusters, nil is returned if none has been defined
+func (n *NamingPolicy) clusters() *NamingRule {
+	if n == nil {
+		return nil
+	}
+	return n.Clusters
+}
+
+// ValidateEnvironmentName checks that the name complies with the naming policy of the project
+func (p *ProjectConfig) ValidateEnvironmentName(name string) error {
+	return p.Naming.environments().Validate(name)
+}
+
+// ValidateStageName checks that the name complies with the naming policy of the project
+func (p *ProjectConfig) ValidateStageName(name string) error {
+	return p.Naming.stages().Validate(name)
+}
+
+// ValidateClusterName checks that the name complies with the naming policy of the project
+// Cluster names must be unique across the project, so the name must not be used by a cluster of another environment or stage
+func (p *ProjectConfig) ValidateClusterName(env, stage, name string) error {
+	err := p.Naming.clusters().Validate(name)
+	if err != nil {
+		return err
+	}
+	return checkUniqueClusterName(name, env+"/"+stage, p.clusterStages()[name])
+}
+
+// clusterStages returns the stages (<env>/<stage>) of all clusters by cluster name, the stages are sorted
+func (p *ProjectConfig) clusterStages() map[string][]string {
+	stages := map[string][]string{}
+	for _, envName := range utils.SortStringSlice(utils.MapKeysToList(p.Environments)) {
+		for _, stageName := range utils.SortStringSlice(utils.MapKeysToList(p.Environments[envName].Stages)) {
+			for clusterName := range p.Environments[envName].Stages[stageName].Clusters {
+				stages[clusterName] = append(stages[clusterName], envName+"/"+stageName)
+			}
+		}
+	}
+	return stages
+}
+
+// checkUniqueClusterName checks that none of the given stages using the name differs from the stage of the cluster
+func checkUniqueClusterName(name, stage string, stages []string) error {
+	for _, other := range stages {
+		if other != stage {
+			return fmt.Errorf("%q is already used by a cluster in %s", name, other)
+		}
+	}
+	return nil
+}
+
+// ValidateNames checks that the names of all environments, stages and clusters comply with the naming policy of the project
+func (p *ProjectConfig) ValidateNames() error {
+	for _, rule := range []*NamingRule{p.Naming.environments(), p.Naming.stages(), p.Naming.clusters()} {
+		err := rule.validatePattern()
+		if err != nil {
+			return err
+		}
+	}
+	// the stages of all clusters are collected once, so the uniqueness of each name is checked by a lookup
+	clusterStages := p.clusterStages()
+	for _, envName := range utils.SortStringSlice(utils.MapKeysToList(p.Environments)) {
+		err := p.ValidateEnvironmentName(envName)
+		if err != nil {
+			return fmt.Errorf("environment %s: %w", envName, err)
+		}
+		env := p.Environments[envName]
+		for _, stageName := range utils.SortStringSlice(utils.MapKeysToList(env.Stages)) {
+			err := p.ValidateStageName(stageName)
+			if err != nil {
+				return fmt.Errorf("stage %s/%s: %w", envName, stageName, err)
+			}
+			for _, clusterName := range utils.SortStringSlice(utils.MapKeysToList(env.Stages[stageName].Clusters)) {
+				err := p.Naming.clusters().Validate(clusterName)
+				if err == nil {
+					err = checkUniqueClusterName(clusterName, envName+"/"+stageName, clusterStages[clusterName])
+				}
+				if err != nil {
+					return fmt.Errorf("cluster %s/%s/%s: %w", envName, stageName, clusterName, err)
+				}
+			}
+		}
+	}
+	return nil
+}
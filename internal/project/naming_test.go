@@ -0,0 +1,229 @@
+package project
+
+import (
+	"testing"
+)
+
+func TestNamingRule_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    *NamingRule
+		value   string
+		wantErr bool
+	}{
+		{
+			name:  "dns label without rule",
+			rule:  nil,
+			value: "cluster-01",
+		},
+		{
+			name:    "uppercase characters",
+			rule:    nil,
+			value:   "Cluster",
+			wantErr: true,
+		},
+		{
+			name:    "underscore",
+			rule:    nil,
+			value:   "my_cluster",
+			wantErr: true,
+		},
+		{
+			name:    "leading dash",
+			rule:    nil,
+			value:   "-cluster",
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			rule:    &NamingRule{},
+			value:   "",
+			wantErr: true,
+		},
+		{
+			name:    "longer than a dns label",
+			rule:    &NamingRule{MaxLength: 100},
+			value:   "a123456789012345678901234567890123456789012345678901234567890123",
+			wantErr: true,
+		},
+		{
+			name:    "exceeds max length",
+			rule:    &NamingRule{MaxLength: 5},
+			value:   "cluster",
+			wantErr: true,
+		},
+		{
+			name:  "within max length",
+			rule:  &NamingRule{MaxLength: 7},
+			value: "cluster",
+		},
+		{
+			name:    "reserved",
+			rule:    &NamingRule{Reserved: []string{"default", "argocd"}},
+			value:   "argocd",
+			wantErr: true,
+		},
+		{
+			name:  "matches pattern",
+			rule:  &NamingRule{Pattern: "^(dev|prod)-[a-z]+$"},
+			value: "dev-hugi",
+		},
+		{
+			name:    "does not match pattern",
+			rule:    &NamingRule{Pattern: "^(dev|prod)-[a-z]+$"},
+			value:   "test-hugi",
+			wantErr: true,
+		},
+		{
+			name:    "invalid pattern",
+			rule:    &NamingRule{Pattern: "("},
+			value:   "hugi",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("NamingRule.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNamingRule_pattern(t *testing.T) {
+	rule := &NamingRule{Pattern: "^dev-"}
+	first, err := rule.pattern()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := rule.pattern()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Errorf("NamingRule.pattern() must compile the pattern only once")
+	}
+
+	rule.Pattern = "^prod-"
+	if err := rule.Validate("prod-hugi"); err != nil {
+		t.Errorf("NamingRule.Validate() with changed pattern error = %v", err)
+	}
+}
+
+func TestProjectConfig_ValidateNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *ProjectConfig
+		wantErr bool
+	}{
+		{
+			name: "valid names",
+			config: &ProjectConfig{
+				Environments: map[string]*Environment{
+					"dev": {Stages: map[string]*Stage{
+						"dev": {Clusters: map[string]*Cluster{"hugi": {}}},
+						"qa":  {Clusters: map[string]*Cluster{"odin": {}}},
+					}},
+					"prod": {Stages: map[string]*Stage{
+						"dev": {Clusters: map[string]*Cluster{"thor": {}}},
+					}},
+				},
+			},
+		},
+		{
+			name: "invalid environment name",
+			config: &ProjectConfig{
+				Environments: map[string]*Environment{
+					"Dev": {},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "stage name violates the policy",
+			config: &ProjectConfig{
+				Naming: &NamingPolicy{Stages: &NamingRule{Reserved: []string{"default"}}},
+				Environments: map[string]*Environment{
+					"dev": {Stages: map[string]*Stage{"default": {}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cluster name used in another stage",
+			config: &ProjectConfig{
+				Environments: map[string]*Environment{
+					"dev": {Stages: map[string]*Stage{
+						"dev": {Clusters: map[string]*Cluster{"hugi": {}}},
+					}},
+					"prod": {Stages: map[string]*Stage{
+						"prod": {Clusters: map[string]*Cluster{"hugi": {}}},
+					}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid pattern without names",
+			config: &ProjectConfig{
+				Naming: &NamingPolicy{Clusters: &NamingRule{Pattern: "["}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.ValidateNames(); (err != nil) != tt.wantErr {
+				t.Errorf("ProjectConfig.ValidateNames() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProjectConfig_ValidateClusterName(t *testing.T) {
+	config := &ProjectConfig{
+		Naming: &NamingPolicy{Clusters: &NamingRule{MaxLength: 10}},
+		Environments: map[string]*Environment{
+			"dev": {Stages: map[string]*Stage{
+				"dev": {Clusters: map[string]*Cluster{"hugi": {}}},
+				"qa":  {Clusters: map[string]*Cluster{}},
+			}},
+		},
+	}
+	tests := []struct {
+		name    string
+		stage   string
+		cluster string
+		wantErr bool
+	}{
+		{
+			name:    "new name",
+			stage:   "qa",
+			cluster: "odin",
+		},
+		{
+			name:    "same stage",
+			stage:   "dev",
+			cluster: "hugi",
+		},
+		{
+			name:    "used by another stage",
+			stage:   "qa",
+			cluster: "hugi",
+			wantErr: true,
+		},
+		{
+			name:    "violates the policy",
+			stage:   "qa",
+			cluster: "a-very-long-name",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := config.ValidateClusterName("dev", tt.stage, tt.cluster); (err != nil) != tt.wantErr {
+				t.Errorf("ProjectConfig.ValidateClusterName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
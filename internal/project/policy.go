@@ -0,0 +1,276 @@
+package project
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
+)
+
+// Policy defines rules for the resolved addon state and properties of the clusters of the selected environments and stages
+type Policy struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Environments and Stages select the clusters the policy applies to, the policy applies to all clusters if both are empty
+	Environments []string `json:"environments,omitempty"`
+	Stages       []string `json:"stages,omitempty"`
+	// RequiredAddons must be enabled for the clusters
+	RequiredAddons []string `json:"requiredAddons,omitempty"`
+	// ForbiddenAddons must not be enabled for the clusters
+	ForbiddenAddons []string `json:"forbiddenAddons,omitempty"`
+	// PinnedAddons must be enabled and their properties must have the given values
+	PinnedAddons map[string]map[string]any `json:"pinnedAddons,omitempty"`
+	// Rules are CEL expressions that must evaluate to true
+	Rules []PolicyRule `json:"rules,omitempty"`
+}
+
+// PolicyRule is a CEL expression that must evaluate to true
+// The expression has access to environment, stage, cluster, labels, properties and addons
+// Each addon is a map with the keys enabled, group and properties
+type PolicyRule struct {
+	Expression string `json:"expression"`
+	// Message is reported if the expression evaluates to false
+	Message string `json:"message,omitempty"`
+}
+
+// PolicyViolation is a policy rule that is not satisfied by a cluster
+type PolicyViolation struct {
+	Policy      string `json:"policy"`
+	Environment string `json:"environment"`
+	Stage       string `json:"stage"`
+	Cluster     string `json:"cluster"`
+	Message     string `json:"message"`
+}
+
+func (v PolicyViolation) String() string {
+	return fmt.Sprintf("%s/%s/%s: %s (%s)", v.Environment, v.Stage, v.Cluster, v.Message, v.Policy)
+}
+
+// appliesTo checks if the policy selects the given environment and stage
+func (p Policy) appliesTo(env, stage string) bool {
+	if len(p.Environments) > 0 && !slices.Contains(p.Environments, env) {
+		return false
+	}
+	if len(p.Stages) > 0 && !slices.Contains(p.Stages, stage) {
+		return false
+	}
+	return true
+}
+
+// policyEnv declares the variables available to the expressions of policy rules
+func policyEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("environment", cel.StringType),
+		cel.Variable("stage", cel.StringType),
+		cel.Variable("cluster", cel.StringType),
+		cel.Variable("labels", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("properties", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("addons", cel.MapType(cel.StringType, cel.DynType)),
+	)
+}
+
+// policyProgram compiles the expression, compiled expressions are cached
+func (p *ProjectConfig) policyProgram(expression string) (cel.Program, error) {
+	if program, ok := p.policyPrograms[expression]; ok {
+		return program, nil
+	}
+	env, err := policyEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", expression, issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("expression %q must evaluate to bool, but evaluates to %s", expression, ast.OutputType())
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", expression, err)
+	}
+	if p.policyPrograms == nil {
+		p.policyPrograms = map[string]cel.Program{}
+	}
+	p.policyPrograms[expression] = program
+	return program, nil
+}
+
+// ValidatePolicies checks that all policies have a unique name and that their expressions compile
+func (p *ProjectConfig) ValidatePolicies() error {
+	names := map[string]bool{}
+	for _, policy := range p.Policies {
+		if policy.Name == "" {
+			return fmt.Errorf("policy name cannot be empty")
+		}
+		if names[policy.Name] {
+			return fmt.Errorf("policy %s is defined more than once", policy.Name)
+		}
+		names[policy.Name] = true
+		for _, rule := range policy.Rules {
+			_, err := p.policyProgram(rule.Expression)
+			if err != nil {
+				return fmt.Errorf("policy %s: %w", policy.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckPolicies evaluates the policies against all clusters of the project
+func (p *ProjectConfig) CheckPolicies() ([]PolicyViolation, error) {
+	violations := []PolicyViolation{}
+	for _, envName := range utils.SortStringSlice(utils.MapKeysToList(p.Environments)) {
+		env := p.Environments[envName]
+		for _, stageName := range utils.SortStringSlice(utils.MapKeysToList(env.Stages)) {
+			stage := env.Stages[stageName]
+			for _, clusterName := range utils.SortStringSlice(utils.MapKeysToList(stage.Clusters)) {
+				cluster := stage.Clusters[clusterName]
+				if cluster.Name == "" {
+					cluster.Name = clusterName
+				}
+				result, err := p.CheckClusterPolicies(envName, stageName, cluster)
+				if err != nil {
+					return nil, err
+				}
+				violations = append(violations, result...)
+			}
+		}
+	}
+	return violations, nil
+}
+
+// CheckClusterPolicies evaluates the policies of the environment and stage against the resolved addon state and properties of the cluster
+// The cluster does not need to be part of the project yet, so it can be checked before it is saved
+func (p *ProjectConfig) CheckClusterPolicies(env, stage string, c *Cluster) ([]PolicyViolation, error) {
+	violations := []PolicyViolation{}
+	policies := []Policy{}
+	for _, policy := range p.Policies {
+		if policy.appliesTo(env, stage) {
+			policies = append(policies, policy)
+		}
+	}
+	if len(policies) == 0 {
+		return violations, nil
+	}
+
+	properties, err := c.ResolvedProperties(p, env, stage)
+	if err != nil {
+		return nil, err
+	}
+	addons, err := c.addonData(p, env, stage, properties)
+	if err != nil {
+		return nil, err
+	}
+	addonValues := map[string]any{}
+	for name, addon := range addons {
+		addonValues[name] = map[string]any{
+			"enabled":    addon.Enabled,
+			"group":      addon.Group,
+			"properties": addon.Properties,
+		}
+	}
+	labels := map[string]string{}
+	for k, v := range c.Labels {
+		labels[k] = v
+	}
+	variables := map[string]any{
+		"environment": env,
+		"stage":       stage,
+		"cluster":     c.Name,
+		"labels":      labels,
+		"properties":  properties,
+		"addons":      addonValues,
+	}
+
+	for _, policy := range policies {
+		violate := func(format string, args ...any) {
+			violations = append(violations, PolicyViolation{
+				Policy:      policy.Name,
+				Environment: env,
+				Stage:       stage,
+				Cluster:     c.Name,
+				Message:     fmt.Sprintf(format, args...),
+			})
+		}
+
+		for _, name := range policy.RequiredAddons {
+			if !addons[name].Enabled {
+				violate("addon %s must be enabled", name)
+			}
+		}
+		for _, name := range policy.ForbiddenAddons {
+			if addons[name].Enabled {
+				violate("addon %s must not be enabled", name)
+			}
+		}
+		for _, name := range utils.SortStringSlice(utils.MapKeysToList(policy.PinnedAddons)) {
+			addon := addons[name]
+			if !addon.Enabled {
+				violate("addon %s must be enabled", name)
+				continue
+			}
+			pinned := policy.PinnedAddons[name]
+			for _, key := range utils.SortStringSlice(utils.MapKeysToList(pinned)) {
+				want := pinned[key]
+				if definition, ok := p.ParsedAddons[name].Properties[key]; ok {
+					parsed, err := definition.ParseValue(want)
+					if err != nil {
+						return nil, fmt.Errorf("policy %s: pinned property %s of addon %s: %w", policy.Name, key, name, err)
+					}
+					want = parsed
+				}
+				got, ok := addon.Properties[key]
+				if !ok || !reflect.DeepEqual(got, want) {
+					violate("property %s of addon %s must be %v, but is %v", key, name, want, got)
+				}
+			}
+		}
+
+		for _, rule := range policy.Rules {
+			program, err := p.policyProgram(rule.Expression)
+			if err != nil {
+				return nil, fmt.Errorf("policy %s: %w", policy.Name, err)
+			}
+			out, _, err := program.Eval(variables)
+			if err != nil {
+				return nil, fmt.Errorf("policy %s: failed to evaluate %q for cluster %s: %w", policy.Name, rule.Expression, c.Name, err)
+			}
+			satisfied, ok := out.Value().(bool)
+			if !ok {
+				return nil, fmt.Errorf("policy %s: expression %q evaluates to %v instead of bool", policy.Name, rule.Expression, out.Value())
+			}
+			if satisfied {
+				continue
+			}
+			if rule.Message != "" {
+				violate("%s", rule.Message)
+				continue
+			}
+			violate("expression %s is not satisfied", rule.Expression)
+		}
+	}
+	return violations, nil
+}
+
+// PolicyViolations is the error returned for clusters that violate the policies of the project
+type PolicyViolations []PolicyViolation
+
+func (v PolicyViolations) Error() string {
+	messages := []string{}
+	for _, violation := range v {
+		messages = append(messages, violation.String())
+	}
+	return "policy violations:\n" + strings.Join(messages, "\n")
+}
+
+// PolicyError combines the policy violations into a single error, nil is returned if there are no violations
+func PolicyError(violations []PolicyViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return PolicyViolations(violations)
+}
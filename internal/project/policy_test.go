@@ -0,0 +1,178 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+)
+
+func TestProjectConfig_CheckPolicies(t *testing.T) {
+	newConfig := func(policies ...Policy) *ProjectConfig {
+		return &ProjectConfig{
+			Policies: policies,
+			Addons: map[string]Addon{
+				"monitoring": {DefaultEnabled: true},
+				"kyverno":    {DefaultEnabled: true},
+				"debug":      {DefaultEnabled: false},
+			},
+			ParsedAddons: map[string]template.TemplateManifest{
+				"monitoring": {Name: "monitoring", Group: "apps"},
+				"kyverno": {Name: "kyverno", Group: "security", Properties: map[string]template.Property{
+					"enforce":  {Type: template.PropertyTypeBool, Default: false},
+					"replicas": {Type: template.PropertyTypeInt, Default: 1},
+				}},
+				"debug": {Name: "debug", Group: "apps"},
+			},
+			Environments: map[string]*Environment{
+				"dev": {Stages: map[string]*Stage{
+					"dev": {Clusters: map[string]*Cluster{
+						"hugi": {Addons: map[string]*ClusterAddon{"debug": {Enabled: boolPtr(true)}}},
+					}},
+				}},
+				"prod": {Stages: map[string]*Stage{
+					"prod": {Clusters: map[string]*Cluster{
+						"odin": {
+							Labels: map[string]string{"region": "eu"},
+							Addons: map[string]*ClusterAddon{
+								"monitoring": {Enabled: boolPtr(false)},
+								"kyverno":    {Properties: map[string]any{"enforce": true, "replicas": 3}},
+							},
+							Properties: map[string]any{"tier": "gold"},
+						},
+					}},
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		config  *ProjectConfig
+		want    []PolicyViolation
+		wantErr bool
+	}{
+		{
+			name:   "without policies",
+			config: newConfig(),
+			want:   []PolicyViolation{},
+		},
+		{
+			name: "required and forbidden addons",
+			config: newConfig(
+				Policy{Name: "prod-monitoring", Stages: []string{"prod"}, RequiredAddons: []string{"monitoring", "kyverno"}},
+				Policy{Name: "no-debug", ForbiddenAddons: []string{"debug"}},
+			),
+			want: []PolicyViolation{
+				{Policy: "no-debug", Environment: "dev", Stage: "dev", Cluster: "hugi", Message: "addon debug must not be enabled"},
+				{Policy: "prod-monitoring", Environment: "prod", Stage: "prod", Cluster: "odin", Message: "addon monitoring must be enabled"},
+			},
+		},
+		{
+			name: "pinned addons",
+			config: newConfig(
+				Policy{Name: "enforce", Environments: []string{"prod", "dev"}, PinnedAddons: map[string]map[string]any{
+					"kyverno": {"enforce": true, "replicas": float64(3)},
+				}},
+			),
+			want: []PolicyViolation{
+				{Policy: "enforce", Environment: "dev", Stage: "dev", Cluster: "hugi", Message: "property enforce of addon kyverno must be true, but is false"},
+				{Policy: "enforce", Environment: "dev", Stage: "dev", Cluster: "hugi", Message: "property replicas of addon kyverno must be 3, but is 1"},
+			},
+		},
+		{
+			name: "rules",
+			config: newConfig(
+				Policy{Name: "rules", Stages: []string{"prod"}, Rules: []PolicyRule{
+					{Expression: `addons.kyverno.properties.replicas >= 3`},
+					{Expression: `labels.region == "us"`, Message: "prod clusters must run in the us"},
+					{Expression: `properties.tier == "gold" && cluster.startsWith("o") && environment == stage`},
+					{Expression: `!addons.debug.enabled`},
+				}},
+			),
+			want: []PolicyViolation{
+				{Policy: "rules", Environment: "prod", Stage: "prod", Cluster: "odin", Message: "prod clusters must run in the us"},
+			},
+		},
+		{
+			name: "rule without result",
+			config: newConfig(
+				Policy{Name: "rules", Rules: []PolicyRule{{Expression: `properties.unknown == "x"`}}},
+			),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.CheckPolicies()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProjectConfig.CheckPolicies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ProjectConfig.CheckPolicies() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProjectConfig_ValidatePolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies []Policy
+		wantErr  bool
+	}{
+		{
+			name:     "valid",
+			policies: []Policy{{Name: "a", Rules: []PolicyRule{{Expression: `stage == "prod"`}}}, {Name: "b"}},
+		},
+		{
+			name:     "missing name",
+			policies: []Policy{{RequiredAddons: []string{"monitoring"}}},
+			wantErr:  true,
+		},
+		{
+			name:     "duplicate name",
+			policies: []Policy{{Name: "a"}, {Name: "a"}},
+			wantErr:  true,
+		},
+		{
+			name:     "syntax error",
+			policies: []Policy{{Name: "a", Rules: []PolicyRule{{Expression: `stage ==`}}}},
+			wantErr:  true,
+		},
+		{
+			name:     "not a bool",
+			policies: []Policy{{Name: "a", Rules: []PolicyRule{{Expression: `stage + "x"`}}}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &ProjectConfig{Policies: tt.policies}
+			if err := p.ValidatePolicies(); (err != nil) != tt.wantErr {
+				t.Errorf("ProjectConfig.ValidatePolicies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPolicyError(t *testing.T) {
+	if err := PolicyError(nil); err != nil {
+		t.Errorf("PolicyError() = %v, want nil", err)
+	}
+	violations := []PolicyViolation{{Environment: "dev", Stage: "dev", Cluster: "hugi", Policy: "monitoring", Message: "addon monitoring must be enabled"}}
+	err := fmt.Errorf("failed to render: %w", PolicyError(violations))
+	var got PolicyViolations
+	if !errors.As(err, &got) {
+		t.Fatalf("PolicyError() must be detectable as PolicyViolations, got %v", err)
+	}
+	if diff := cmp.Diff(PolicyViolations(violations), got); diff != "" {
+		t.Errorf("PolicyError() mismatch (-want +got):\n%s", diff)
+	}
+	want := "policy violations:\ndev/dev/hugi: addon monitoring must be enabled (monitoring)"
+	if got.Error() != want {
+		t.Errorf("PolicyViolations.Error() = %q, want %q", got.Error(), want)
+	}
+}
@@ -0,0 +1,161 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
+)
+
+// PropertyLevel is the level of the project hierarchy a property is defined at
+type PropertyLevel string
+
+const (
+	PropertyLevelEnvironment PropertyLevel = "environment"
+	PropertyLevelStage       PropertyLevel = "stage"
+	PropertyLevelCluster     PropertyLevel = "cluster"
+)
+
+// PropertyDefinition describes a property that can be set on environments, stages and clusters
+type PropertyDefinition struct {
+	template.Property
+	// RequiredAt is the level at which the property must be set, either on the level itself or on one of its parents
+	// If not defined and the property is required, the property must be set at the cluster level
+	RequiredAt PropertyLevel `json:"requiredAt,omitempty"`
+}
+
+// requiredAt returns the level at which the property must be set or an empty string if the property is optional
+func (pd PropertyDefinition) requiredAt() PropertyLevel {
+	if pd.RequiredAt == "" && pd.Required {
+		return PropertyLevelCluster
+	}
+	return pd.RequiredAt
+}
+
+// parse validates the given value against the definition, required properties are checked by ValidateProperties
+func (pd PropertyDefinition) parse(value any) (any, error) {
+	property := pd.Property
+	property.Required = false
+	property.Default = nil
+	return property.ParseValue(value)
+}
+
+// ParsePropertyValue validates the value against the project property schema and returns the parsed value
+// Properties that are not part of the schema are kept as they are
+func (p *ProjectConfig) ParsePropertyValue(key string, value any) (any, error) {
+	definition, ok := p.PropertySchema[key]
+	if !ok {
+		return value, nil
+	}
+	return definition.parse(value)
+}
+
+// PropertyDefaults returns the default values of the project property schema
+func (p *ProjectConfig) PropertyDefaults() map[string]any {
+	defaults := map[string]any{}
+	for key, definition := range p.PropertySchema {
+		if definition.Default == nil {
+			continue
+		}
+		defaults[key] = definition.Default
+	}
+	return defaults
+}
+
+// ValidateProperties validates the properties of all environments, stages and clusters against the project property schema
+// Valid values are replaced by their parsed representation, e.g. a yaml number for an int property is converted to an int
+func (p *ProjectConfig) ValidateProperties() error {
+	for key, definition := range p.PropertySchema {
+		switch definition.requiredAt() {
+		case "", PropertyLevelEnvironment, PropertyLevelStage, PropertyLevelCluster:
+		default:
+			return fmt.Errorf("property %s has an unknown required level %s", key, definition.RequiredAt)
+		}
+		err := definition.ValidatePatterns()
+		if err != nil {
+			return fmt.Errorf("property %s: %w", key, err)
+		}
+		if definition.Default == nil {
+			continue
+		}
+		_, err = definition.parse(definition.Default)
+		if err != nil {
+			return fmt.Errorf("default of property %s is invalid: %w", key, err)
+		}
+	}
+
+	for envName, env := range p.Environments {
+		err := p.parseProperties(env.Properties)
+		if err != nil {
+			return fmt.Errorf("environment %s: %w", envName, err)
+		}
+		err = p.CheckRequiredProperties(PropertyLevelEnvironment, p.PropertiesAt(env.Properties))
+		if err != nil {
+			return fmt.Errorf("environment %s: %w", envName, err)
+		}
+		for stageName, stage := range env.Stages {
+			err := p.parseProperties(stage.Properties)
+			if err != nil {
+				return fmt.Errorf("stage %s/%s: %w", envName, stageName, err)
+			}
+			err = p.CheckRequiredProperties(PropertyLevelStage, p.PropertiesAt(env.Properties, stage.Properties))
+			if err != nil {
+				return fmt.Errorf("stage %s/%s: %w", envName, stageName, err)
+			}
+			for clusterName, cluster := range stage.Clusters {
+				err := p.parseProperties(cluster.Properties)
+				if err != nil {
+					return fmt.Errorf("cluster %s/%s/%s: %w", envName, stageName, clusterName, err)
+				}
+				err = p.CheckRequiredProperties(PropertyLevelCluster, p.PropertiesAt(env.Properties, stage.Properties, cluster.Properties))
+				if err != nil {
+					return fmt.Errorf("cluster %s/%s/%s: %w", envName, stageName, clusterName, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// parseProperties validates and replaces all values of the given properties that are part of the schema
+func (p *ProjectConfig) parseProperties(properties map[string]any) error {
+	for key, value := range properties {
+		parsed, err := p.ParsePropertyValue(key, value)
+		if err != nil {
+			return fmt.Errorf("property %s is invalid: %w", key, err)
+		}
+		properties[key] = parsed
+	}
+	return nil
+}
+
+// PropertiesAt merges the defaults of the schema with the given properties, the last properties have the highest priority
+func (p *ProjectConfig) PropertiesAt(properties ...map[string]any) map[string]any {
+	merged := p.PropertyDefaults()
+	for _, props := range properties {
+		merged = template.MergeProperties(p.PropertyDefinitions(), merged, props)
+	}
+	return merged
+}
+
+// PropertyDefinitions returns the property definitions of the project property schema
+func (p *ProjectConfig) PropertyDefinitions() map[string]template.Property {
+	definitions := make(map[string]template.Property, len(p.PropertySchema))
+	for key, definition := range p.PropertySchema {
+		definitions[key] = definition.Property
+	}
+	return definitions
+}
+
+// CheckRequiredProperties checks if all properties required at the given level are set in the merged properties
+func (p *ProjectConfig) CheckRequiredProperties(level PropertyLevel, merged map[string]any) error {
+	for _, key := range utils.SortStringSlice(utils.MapKeysToList(p.PropertySchema)) {
+		if p.PropertySchema[key].requiredAt() != level {
+			continue
+		}
+		if merged[key] == nil {
+			return fmt.Errorf("property %s is required at the %s level", key, level)
+		}
+	}
+	return nil
+}
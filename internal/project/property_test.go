@@ -0,0 +1,222 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+)
+
+func TestProjectConfig_ParsePropertyValue(t *testing.T) {
+	schema := map[string]PropertyDefinition{
+		"replicas": {Property: template.Property{Type: template.PropertyTypeInt}},
+		"tier":     {Property: template.Property{Type: template.PropertyTypeEnum, Enum: []any{"gold", "silver"}}},
+	}
+	tests := []struct {
+		name    string
+		key     string
+		value   any
+		want    any
+		wantErr bool
+	}{
+		{
+			name:  "int from string",
+			key:   "replicas",
+			value: "3",
+			want:  3,
+		},
+		{
+			name:    "invalid int",
+			key:     "replicas",
+			value:   "three",
+			wantErr: true,
+		},
+		{
+			name:    "enum not allowed",
+			key:     "tier",
+			value:   "bronze",
+			wantErr: true,
+		},
+		{
+			name:  "unknown key is kept",
+			key:   "gitBranch",
+			value: "main",
+			want:  "main",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &ProjectConfig{PropertySchema: schema}
+			got, err := p.ParsePropertyValue(tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ProjectConfig.ParsePropertyValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ProjectConfig.ParsePropertyValue() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProjectConfig_ValidateProperties(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *ProjectConfig
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "values are parsed",
+			config: &ProjectConfig{
+				PropertySchema: map[string]PropertyDefinition{
+					"replicas": {Property: template.Property{Type: template.PropertyTypeInt}},
+				},
+				Environments: map[string]*Environment{
+					"dev": {
+						Stages: map[string]*Stage{
+							"dev": {
+								Clusters: map[string]*Cluster{
+									"cluster1": {Properties: map[string]any{"replicas": float64(2)}},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: map[string]any{"replicas": 2},
+		},
+		{
+			name: "required at environment is set",
+			config: &ProjectConfig{
+				PropertySchema: map[string]PropertyDefinition{
+					"region": {Property: template.Property{Type: template.PropertyTypeString}, RequiredAt: PropertyLevelEnvironment},
+				},
+				Environments: map[string]*Environment{
+					"dev": {
+						Properties: map[string]any{"region": "eu"},
+						Stages: map[string]*Stage{
+							"dev": {
+								Clusters: map[string]*Cluster{
+									"cluster1": {},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "required at environment is missing",
+			config: &ProjectConfig{
+				PropertySchema: map[string]PropertyDefinition{
+					"region": {Property: template.Property{Type: template.PropertyTypeString}, RequiredAt: PropertyLevelEnvironment},
+				},
+				Environments: map[string]*Environment{
+					"dev": {
+						Stages: map[string]*Stage{
+							"dev": {Properties: map[string]any{"region": "eu"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "required defaults to cluster",
+			config: &ProjectConfig{
+				PropertySchema: map[string]PropertyDefinition{
+					"ip": {Property: template.Property{Type: template.PropertyTypeString, Required: true}},
+				},
+				Environments: map[string]*Environment{
+					"dev": {
+						Stages: map[string]*Stage{
+							"dev": {
+								Clusters: map[string]*Cluster{
+									"cluster1": {},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "required is satisfied by default",
+			config: &ProjectConfig{
+				PropertySchema: map[string]PropertyDefinition{
+					"ip": {Property: template.Property{Type: template.PropertyTypeString, Required: true, Default: "10.0.0.1"}},
+				},
+				Environments: map[string]*Environment{
+					"dev": {
+						Stages: map[string]*Stage{
+							"dev": {
+								Clusters: map[string]*Cluster{
+									"cluster1": {},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "invalid default",
+			config: &ProjectConfig{
+				PropertySchema: map[string]PropertyDefinition{
+					"replicas": {Property: template.Property{Type: template.PropertyTypeInt, Default: "abc"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid pattern",
+			config: &ProjectConfig{
+				PropertySchema: map[string]PropertyDefinition{
+					"zone": {Property: template.Property{Type: template.PropertyTypeString, Pattern: "["}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid nested pattern",
+			config: &ProjectConfig{
+				PropertySchema: map[string]PropertyDefinition{
+					"zones": {Property: template.Property{Type: template.PropertyTypeList, Items: &template.Property{Type: template.PropertyTypeString, Pattern: "["}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown required level",
+			config: &ProjectConfig{
+				PropertySchema: map[string]PropertyDefinition{
+					"replicas": {Property: template.Property{Type: template.PropertyTypeInt}, RequiredAt: "addon"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.ValidateProperties()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ProjectConfig.ValidateProperties() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.want == nil {
+				return
+			}
+			got := tt.config.GetCluster("dev", "dev", "cluster1").Properties
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ProjectConfig.ValidateProperties() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package project
+
+import (
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
+)
+
+// PropertyOrigin describes the level a property value has been defined at
+type PropertyOrigin string
+
+const (
+	// PropertyOriginDefault is used for the defaults of the property schema and the addon manifests
+	PropertyOriginDefault     PropertyOrigin = "default"
+	PropertyOriginEnvironment PropertyOrigin = "environment"
+	PropertyOriginStage       PropertyOrigin = "stage"
+	PropertyOriginCluster     PropertyOrigin = "cluster"
+)
+
+// PropertyLayer contains the properties defined at a single level
+type PropertyLayer struct {
+	Origin     PropertyOrigin
+	Properties map[string]any
+}
+
+// PropertyLayers is a list of property layers, the last layer has the highest priority
+type PropertyLayers []PropertyLayer
+
+// PropertyValue is a property value together with the level it has been defined at
+type PropertyValue struct {
+	Value  any            `json:"value"`
+	Origin PropertyOrigin `json:"origin"`
+}
+
+// ExplainedProperty is the effective value of a property together with the values it shadows
+type ExplainedProperty struct {
+	Key string `json:"key"`
+	PropertyValue
+	// Shadowed contains the values that have been overwritten by the effective value, the most specific one first
+	// Unset (nil) values are not listed
+	Shadowed []PropertyValue `json:"shadowed,omitempty"`
+}
+
+// Merge deep merges the properties of all layers using the merge strategies of the given property definitions
+func (pl PropertyLayers) Merge(schema map[string]template.Property) map[string]any {
+	result := map[string]any{}
+	for _, layer := range pl {
+		result = template.MergeProperties(schema, result, layer.Properties)
+	}
+	return result
+}
+
+// Explain returns the effective value and origin of all properties sorted by key
+// The origin is the most specific layer that contributed to the value, removed properties have a nil value
+// Values of secret properties are masked
+func (pl PropertyLayers) Explain(schema map[string]template.Property) []ExplainedProperty {
+	explained := map[string]*ExplainedProperty{}
+	merged := map[string]any{}
+	for _, layer := range pl {
+		merged = template.MergeProperties(schema, merged, layer.Properties)
+		for key := range layer.Properties {
+			ep, ok := explained[key]
+			if !ok {
+				ep = &ExplainedProperty{Key: key}
+				explained[key] = ep
+			} else if ep.Value != nil {
+				ep.Shadowed = append([]PropertyValue{ep.PropertyValue}, ep.Shadowed...)
+			}
+			ep.PropertyValue = PropertyValue{Value: merged[key], Origin: layer.Origin}
+		}
+	}
+
+	result := make([]ExplainedProperty, 0, len(explained))
+	for _, key := range utils.SortStringSlice(utils.MapKeysToList(explained)) {
+		result = append(result, *explained[key])
+	}
+	return maskSecrets(schema, result)
+}
+
+// PropertyLayersAt returns the property layers of the schema defaults, the environment and the stage (if given)
+// The cluster layer must be added by the caller
+func (pc *ProjectConfig) PropertyLayersAt(env, stage string) PropertyLayers {
+	layers := PropertyLayers{
+		{Origin: PropertyOriginDefault, Properties: pc.PropertyDefaults()},
+	}
+	if env == "" {
+		return layers
+	}
+	layers = append(layers, PropertyLayer{Origin: PropertyOriginEnvironment, Properties: pc.GetEnvironment(env).Properties})
+	if stage == "" {
+		return layers
+	}
+	return append(layers, PropertyLayer{Origin: PropertyOriginStage, Properties: pc.GetStage(env, stage).Properties})
+}
+
+// AddonPropertyLayersAt returns the property layers of the addon defaults and the addon settings of the environment and stage (if given)
+// The cluster layer must be added by the caller
+func (pc *ProjectConfig) AddonPropertyLayersAt(addon, env, stage string) PropertyLayers {
+	defaults := map[string]any{}
+	for key, property := range pc.ParsedAddons[addon].Properties {
+		defaults[key] = property.Default
+	}
+	layers := PropertyLayers{
+		{Origin: PropertyOriginDefault, Properties: defaults},
+	}
+	if env == "" {
+		return layers
+	}
+	if ca := pc.GetEnvironment(env).GetAddon(addon); ca != nil {
+		layers = append(layers, PropertyLayer{Origin: PropertyOriginEnvironment, Properties: ca.Properties})
+	}
+	if stage == "" {
+		return layers
+	}
+	if ca := pc.GetStage(env, stage).GetAddon(addon); ca != nil {
+		layers = append(layers, PropertyLayer{Origin: PropertyOriginStage, Properties: ca.Properties})
+	}
+	return layers
+}
@@ -0,0 +1,78 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPropertyLayers_Explain(t *testing.T) {
+	tests := []struct {
+		name   string
+		layers PropertyLayers
+		want   []ExplainedProperty
+	}{
+		{
+			name:   "no layers",
+			layers: PropertyLayers{},
+			want:   []ExplainedProperty{},
+		},
+		{
+			name: "values from different levels",
+			layers: PropertyLayers{
+				{Origin: PropertyOriginDefault, Properties: map[string]any{"replicas": 1}},
+				{Origin: PropertyOriginEnvironment, Properties: map[string]any{"gitBranch": "main"}},
+				{Origin: PropertyOriginCluster, Properties: map[string]any{"name": "hugi"}},
+			},
+			want: []ExplainedProperty{
+				{Key: "gitBranch", PropertyValue: PropertyValue{Value: "main", Origin: PropertyOriginEnvironment}},
+				{Key: "name", PropertyValue: PropertyValue{Value: "hugi", Origin: PropertyOriginCluster}},
+				{Key: "replicas", PropertyValue: PropertyValue{Value: 1, Origin: PropertyOriginDefault}},
+			},
+		},
+		{
+			name: "shadowed values",
+			layers: PropertyLayers{
+				{Origin: PropertyOriginDefault, Properties: map[string]any{"replicas": 1}},
+				{Origin: PropertyOriginEnvironment, Properties: map[string]any{"replicas": 2}},
+				{Origin: PropertyOriginStage, Properties: map[string]any{}},
+				{Origin: PropertyOriginCluster, Properties: map[string]any{"replicas": 3}},
+			},
+			want: []ExplainedProperty{
+				{
+					Key:           "replicas",
+					PropertyValue: PropertyValue{Value: 3, Origin: PropertyOriginCluster},
+					Shadowed: []PropertyValue{
+						{Value: 2, Origin: PropertyOriginEnvironment},
+						{Value: 1, Origin: PropertyOriginDefault},
+					},
+				},
+			},
+		},
+		{
+			name: "unset values are not shadowed",
+			layers: PropertyLayers{
+				{Origin: PropertyOriginDefault, Properties: map[string]any{"replicas": nil}},
+				{Origin: PropertyOriginStage, Properties: map[string]any{"replicas": 2}},
+			},
+			want: []ExplainedProperty{
+				{Key: "replicas", PropertyValue: PropertyValue{Value: 2, Origin: PropertyOriginStage}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.layers.Explain(nil)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("PropertyLayers.Explain() mismatch (-want +got):\n%s", diff)
+			}
+			// the effective values must match the merged properties
+			merged := tt.layers.Merge(nil)
+			for _, ep := range got {
+				if diff := cmp.Diff(merged[ep.Key], ep.Value); diff != "" {
+					t.Errorf("PropertyLayers.Merge() mismatch for %s (-want +got):\n%s", ep.Key, diff)
+				}
+			}
+		})
+	}
+}
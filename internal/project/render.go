@@ -0,0 +1,33 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+)
+
+// renderScopedTemplates renders all base templates of the given scope with the given template data and returns the rendered files
+func renderScopedTemplates(config *ProjectConfig, scope template.TemplateScope, td template.TemplateData) ([]template.RenderedFile, error) {
+	templates, err := template.LoadTemplateManifest(config.TemplateBasePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load base templates: %w", err)
+	}
+
+	helpers, err := template.LoadHelpers(config.HelpersPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load helpers: %w", err)
+	}
+
+	rendered := []template.RenderedFile{}
+	for _, t := range templates {
+		if t.TemplateManifest.GetScope() != scope {
+			continue
+		}
+		files, err := t.Render(config.BasePath, helpers, td)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s template %s: %w", scope, t.TemplateManifest.Name, err)
+		}
+		rendered = append(rendered, files...)
+	}
+	return rendered, nil
+}
@@ -0,0 +1,180 @@
+package project
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/secret"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+)
+
+// SecretKey returns the key used to encrypt and decrypt secret properties
+func (p *ProjectConfig) SecretKey() (*secret.Key, error) {
+	if p.secretKey != nil {
+		return p.secretKey, nil
+	}
+	key, err := secret.LoadKey(p.SecretKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	p.secretKey = key
+	return key, nil
+}
+
+// EncryptSecret encrypts the given secret value with the key of the project
+func (p *ProjectConfig) EncryptSecret(value any) (any, error) {
+	s, ok := value.(string)
+	if !ok || s == template.DeleteMarker {
+		return value, nil
+	}
+	key, err := p.SecretKey()
+	if err != nil {
+		return nil, err
+	}
+	return key.Encrypt(s)
+}
+
+// EncryptSecrets encrypts all plain text values of secret properties of the environments, stages and clusters
+// If no key has been configured, the values are kept in plain text and reported by the linter
+func (p *ProjectConfig) EncryptSecrets() error {
+	_, err := p.SecretKey()
+	if errors.Is(err, secret.ErrNoKey) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	encrypt := func(schema map[string]template.Property, properties map[string]any) error {
+		for key, value := range properties {
+			if schema[key].Type != template.PropertyTypeSecret {
+				continue
+			}
+			encrypted, err := p.EncryptSecret(value)
+			if err != nil {
+				return fmt.Errorf("property %s: %w", key, err)
+			}
+			properties[key] = encrypted
+		}
+		return nil
+	}
+	encryptAddons := func(addons map[string]*ClusterAddon) error {
+		for addonName, addon := range addons {
+			if addon == nil {
+				continue
+			}
+			err := encrypt(p.ParsedAddons[addonName].Properties, addon.Properties)
+			if err != nil {
+				return fmt.Errorf("addon %s: %w", addonName, err)
+			}
+		}
+		return nil
+	}
+
+	for _, env := range p.Environments {
+		err := encrypt(p.PropertyDefinitions(), env.Properties)
+		if err != nil {
+			return err
+		}
+		err = encryptAddons(env.Addons)
+		if err != nil {
+			return err
+		}
+		for _, stage := range env.Stages {
+			err := encrypt(p.PropertyDefinitions(), stage.Properties)
+			if err != nil {
+				return err
+			}
+			err = encryptAddons(stage.Addons)
+			if err != nil {
+				return err
+			}
+			for _, cluster := range stage.Clusters {
+				err := encrypt(p.PropertyDefinitions(), cluster.Properties)
+				if err != nil {
+					return err
+				}
+				err = encryptAddons(cluster.Addons)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// decryptSecrets returns a copy of the properties with all secret values decrypted
+func (p *ProjectConfig) decryptSecrets(schema map[string]template.Property, properties map[string]any) (map[string]any, error) {
+	result := make(map[string]any, len(properties))
+	for key, value := range properties {
+		result[key] = value
+		s, ok := value.(string)
+		if !ok || schema[key].Type != template.PropertyTypeSecret || !secret.IsEncrypted(s) {
+			continue
+		}
+		secretKey, err := p.SecretKey()
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %w", key, err)
+		}
+		decrypted, err := secretKey.Decrypt(s)
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %w", key, err)
+		}
+		result[key] = decrypted
+	}
+	return result, nil
+}
+
+// maskSecretValues returns a copy of the values with the values of secret properties replaced by a mask
+func maskSecretValues(schema map[string]template.Property, values map[string]any) map[string]any {
+	if values == nil {
+		return nil
+	}
+	result := make(map[string]any, len(values))
+	for key, value := range values {
+		result[key] = value
+		if value != nil && value != template.DeleteMarker && schema[key].Type == template.PropertyTypeSecret {
+			result[key] = secret.Mask
+		}
+	}
+	return result
+}
+
+// maskSecrets replaces the values of secret properties with a mask
+func maskSecrets(schema map[string]template.Property, explained []ExplainedProperty) []ExplainedProperty {
+	for idx, ep := range explained {
+		if schema[ep.Key].Type != template.PropertyTypeSecret {
+			continue
+		}
+		if ep.Value != nil {
+			explained[idx].Value = secret.Mask
+		}
+		for sidx := range ep.Shadowed {
+			explained[idx].Shadowed[sidx].Value = secret.Mask
+		}
+	}
+	return explained
+}
+
+// SealingKey returns the public key of the sealed-secrets controller of the environment
+// If no certificate has been configured for the environment, nil is returned
+func (p *ProjectConfig) SealingKey(env string) (*rsa.PublicKey, error) {
+	environment, ok := p.Environments[env]
+	if !ok || environment.SealedSecretsCertificate == "" {
+		return nil, nil
+	}
+	if key, ok := p.sealingKeys[environment.SealedSecretsCertificate]; ok {
+		return key, nil
+	}
+	key, err := secret.LoadSealingCertificate(environment.SealedSecretsCertificate)
+	if err != nil {
+		return nil, fmt.Errorf("environment %s: %w", env, err)
+	}
+	if p.sealingKeys == nil {
+		p.sealingKeys = map[string]*rsa.PublicKey{}
+	}
+	p.sealingKeys[environment.SealedSecretsCertificate] = key
+	return key, nil
+}
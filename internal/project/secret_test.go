@@ -0,0 +1,108 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/secret"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+)
+
+func TestProjectConfig_EncryptSecrets(t *testing.T) {
+	key, err := secret.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(secret.KeyEnvVar, key.String())
+
+	pc := &ProjectConfig{
+		PropertySchema: map[string]PropertyDefinition{
+			"gitToken": {Property: template.Property{Type: template.PropertyTypeSecret}},
+		},
+		ParsedAddons: map[string]template.TemplateManifest{
+			"vault": {
+				Properties: map[string]template.Property{
+					"unsealKey": {Type: template.PropertyTypeSecret},
+				},
+			},
+		},
+		Environments: map[string]*Environment{
+			"dev": {
+				Properties: map[string]any{"gitToken": "env-token", "gitBranch": "main"},
+				Stages: map[string]*Stage{
+					"test": {
+						Clusters: map[string]*Cluster{
+							"hugi": {
+								Properties: map[string]any{"gitToken": "cluster-token"},
+								Addons: map[string]*ClusterAddon{
+									"vault": {Properties: map[string]any{"unsealKey": "unseal"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	err = pc.EncryptSecrets()
+	if err != nil {
+		t.Fatalf("ProjectConfig.EncryptSecrets() error = %v", err)
+	}
+
+	cluster := pc.GetCluster("dev", "test", "hugi")
+	for _, value := range []any{
+		pc.GetEnvironment("dev").Properties["gitToken"],
+		cluster.Properties["gitToken"],
+		cluster.Addons["vault"].Properties["unsealKey"],
+	} {
+		if !secret.IsEncrypted(value.(string)) {
+			t.Errorf("ProjectConfig.EncryptSecrets() expected %v to be encrypted", value)
+		}
+	}
+	if pc.GetEnvironment("dev").Properties["gitBranch"] != "main" {
+		t.Errorf("ProjectConfig.EncryptSecrets() must not encrypt plain properties")
+	}
+
+	properties, err := cluster.ResolvedProperties(pc, "dev", "test")
+	if err != nil {
+		t.Fatalf("Cluster.ResolvedProperties() error = %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"gitToken": "cluster-token", "gitBranch": "main"}, properties); diff != "" {
+		t.Errorf("Cluster.ResolvedProperties() mismatch (-want +got):\n%s", diff)
+	}
+
+	explained := cluster.ExplainProperties(pc, "dev", "test")
+	want := []ExplainedProperty{
+		{Key: "gitBranch", PropertyValue: PropertyValue{Value: "main", Origin: PropertyOriginEnvironment}},
+		{
+			Key:           "gitToken",
+			PropertyValue: PropertyValue{Value: secret.Mask, Origin: PropertyOriginCluster},
+			Shadowed:      []PropertyValue{{Value: secret.Mask, Origin: PropertyOriginEnvironment}},
+		},
+	}
+	if diff := cmp.Diff(want, explained); diff != "" {
+		t.Errorf("Cluster.ExplainProperties() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestProjectConfig_EncryptSecrets_noKey(t *testing.T) {
+	pc := &ProjectConfig{
+		PropertySchema: map[string]PropertyDefinition{
+			"gitToken": {Property: template.Property{Type: template.PropertyTypeSecret}},
+		},
+		Environments: map[string]*Environment{
+			"dev": {Properties: map[string]any{"gitToken": "env-token"}},
+		},
+	}
+	err := pc.EncryptSecrets()
+	if err != nil {
+		t.Fatalf("ProjectConfig.EncryptSecrets() error = %v", err)
+	}
+	if diff := cmp.Diff("env-token", pc.GetEnvironment("dev").Properties["gitToken"]); diff != "" {
+		t.Errorf("ProjectConfig.EncryptSecrets() mismatch (-want +got):\n%s", diff)
+	}
+	_, err = pc.EncryptSecret("env-token")
+	if err != secret.ErrNoKey {
+		t.Errorf("ProjectConfig.EncryptSecret() error = %v, want %v", err, secret.ErrNoKey)
+	}
+}
@@ -1,44 +1,55 @@
 package project
 
+import (
+	"fmt"
+
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+)
+
 var (
-	_ AddonHandler = &Stage{}
+	_ AddonHandler    = &Stage{}
+	_ TemplateHandler = &Stage{}
 )
 
 type Stage struct {
 	Name       string                   `json:"-"`
-	Properties map[string]string        `json:"properties"`
+	Properties map[string]any           `json:"properties"`
 	Actions    Actions                  `json:"actions"`
 	Clusters   map[string]*Cluster      `json:"clusters"`
 	Addons     map[string]*ClusterAddon `json:"addons"`
+	// Templates is the list of base templates that are rendered for the clusters of the stage
+	// If nil, the templates of the environment are used
+	Templates []string `json:"templates"`
 }
 
-// IsAddonEnabled checks if the addon is enabled for the stage
+// IsAddonEnabled checks if the addon has been enabled explicitly for the stage
 func (s Stage) IsAddonEnabled(addon string) bool {
-	_, ok := s.Addons[addon]
-	if !ok {
-		return false
-	}
-	return s.Addons[addon].Enabled
+	return s.Addons[addon].State() == AddonStateEnabled
 }
 
 // EnableAddon enables the addon for the stage by setting the enabled flag to true
 func (s *Stage) EnableAddon(addon string) {
 	if s.Addons[addon] == nil {
-		s.Addons[addon] = &ClusterAddon{
-			Enabled: true,
-		}
-		return
+		s.Addons[addon] = &ClusterAddon{}
 	}
-	s.Addons[addon].Enabled = true
+	s.Addons[addon].Enabled = boolPtr(true)
 }
 
 // DisableAddon disables the addon for the stage by setting the enabled flag to false
 func (s *Stage) DisableAddon(addon string) {
-	if _, ok := s.Addons[addon]; !ok {
-		// already disabled or not found
+	if s.Addons[addon] == nil {
+		s.Addons[addon] = &ClusterAddon{}
+	}
+	s.Addons[addon].Enabled = boolPtr(false)
+}
+
+// InheritAddon resets the enabled flag of the addon, so that the enablement is inherited from the parent
+func (s *Stage) InheritAddon(addon string) {
+	if s.Addons[addon] == nil {
+		// already inherited
 		return
 	}
-	s.Addons[addon].Enabled = false
+	s.Addons[addon].Enabled = nil
 }
 
 // GetAddons returns the environment addons
@@ -51,7 +62,41 @@ func (s *Stage) GetAddon(name string) *ClusterAddon {
 	return s.Addons[name]
 }
 
+// GetTemplates returns the base templates selected for the stage
+func (s *Stage) GetTemplates() []string {
+	return s.Templates
+}
+
+// SetTemplates sets the base templates selected for the stage
+func (s *Stage) SetTemplates(templates []string) {
+	s.Templates = templates
+}
+
 // GetCluster returns the cluster by name
 func (s *Stage) GetCluster(name string) *Cluster {
 	return s.Clusters[name]
 }
+
+// Render renders all stage scoped templates into <basePath>/<env>/<stage> and returns the rendered files
+func (s *Stage) Render(config *ProjectConfig, env string) ([]template.RenderedFile, error) {
+	properties, err := config.decryptSecrets(config.PropertyDefinitions(), config.EnvStageProperty(env, s.Name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt properties of stage %s: %w", s.Name, err)
+	}
+	properties, err = interpolateProperties(interpolationScope{Environment: env, Stage: s.Name}, properties)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve properties of stage %s: %w", s.Name, err)
+	}
+	sealingKey, err := config.SealingKey(env)
+	if err != nil {
+		return nil, err
+	}
+	return renderScopedTemplates(config, template.TemplateScopeStage, template.TemplateData{
+		BasePath:      config.BasePath,
+		Environment:   env,
+		Stage:         s.Name,
+		Properties:    properties,
+		ClusterSource: config.clusterSource(),
+		SealingKey:    sealingKey,
+	})
+}
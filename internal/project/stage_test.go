@@ -1,6 +1,8 @@
 package project
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -11,7 +13,7 @@ func TestStage_IsAddonEnabled(t *testing.T) {
 	type fields struct {
 		Name       string
 		Addons     map[string]*ClusterAddon
-		Properties map[string]string
+		Properties map[string]any
 	}
 	type args struct {
 		addon string
@@ -27,7 +29,7 @@ func TestStage_IsAddonEnabled(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -41,7 +43,7 @@ func TestStage_IsAddonEnabled(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -65,10 +67,10 @@ func TestStage_IsAddonEnabled(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -82,10 +84,10 @@ func TestStage_IsAddonEnabled(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -99,10 +101,10 @@ func TestStage_IsAddonEnabled(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 					"addon2": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -116,10 +118,10 @@ func TestStage_IsAddonEnabled(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -147,7 +149,7 @@ func TestStage_EnableAddon(t *testing.T) {
 	type fields struct {
 		Name       string
 		Addons     map[string]*ClusterAddon
-		Properties map[string]string
+		Properties map[string]any
 	}
 	type args struct {
 		addon string
@@ -169,7 +171,7 @@ func TestStage_EnableAddon(t *testing.T) {
 			want: Stage{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -179,7 +181,7 @@ func TestStage_EnableAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -189,7 +191,7 @@ func TestStage_EnableAddon(t *testing.T) {
 			want: Stage{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -199,7 +201,7 @@ func TestStage_EnableAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -209,7 +211,7 @@ func TestStage_EnableAddon(t *testing.T) {
 			want: Stage{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -219,10 +221,10 @@ func TestStage_EnableAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -232,10 +234,10 @@ func TestStage_EnableAddon(t *testing.T) {
 			want: Stage{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -245,10 +247,10 @@ func TestStage_EnableAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 					"addon2": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -258,10 +260,10 @@ func TestStage_EnableAddon(t *testing.T) {
 			want: Stage{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -289,7 +291,7 @@ func TestStage_DisableAddon(t *testing.T) {
 	type fields struct {
 		Name       string
 		Addons     map[string]*ClusterAddon
-		Properties map[string]string
+		Properties map[string]any
 	}
 	type args struct {
 		addon string
@@ -309,7 +311,9 @@ func TestStage_DisableAddon(t *testing.T) {
 				addon: "addon1",
 			},
 			want: Stage{
-				Addons: map[string]*ClusterAddon{},
+				Addons: map[string]*ClusterAddon{
+					"addon1": {Enabled: boolPtr(false)},
+				},
 			},
 		},
 		{
@@ -317,7 +321,7 @@ func TestStage_DisableAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled:    true,
+						Enabled:    boolPtr(true),
 						Properties: map[string]any{},
 					},
 				},
@@ -328,7 +332,7 @@ func TestStage_DisableAddon(t *testing.T) {
 			want: Stage{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled:    false,
+						Enabled:    boolPtr(false),
 						Properties: map[string]any{},
 					},
 				},
@@ -339,7 +343,7 @@ func TestStage_DisableAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -349,7 +353,7 @@ func TestStage_DisableAddon(t *testing.T) {
 			want: Stage{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled:    false,
+						Enabled:    boolPtr(false),
 						Properties: nil,
 					},
 				},
@@ -377,7 +381,7 @@ func TestStage_DisableAddon(t *testing.T) {
 func TestStage_GetAddons(t *testing.T) {
 	type fields struct {
 		Name       string
-		Properties map[string]string
+		Properties map[string]any
 		Actions    Actions
 		Clusters   map[string]*Cluster
 		Addons     map[string]*ClusterAddon
@@ -399,13 +403,13 @@ func TestStage_GetAddons(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
 			want: map[string]*ClusterAddon{
 				"addon1": {
-					Enabled: true,
+					Enabled: boolPtr(true),
 				},
 			},
 		},
@@ -414,19 +418,19 @@ func TestStage_GetAddons(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
 			want: map[string]*ClusterAddon{
 				"addon1": {
-					Enabled: true,
+					Enabled: boolPtr(true),
 				},
 				"addon2": {
-					Enabled: false,
+					Enabled: boolPtr(false),
 				},
 			},
 		},
@@ -450,7 +454,7 @@ func TestStage_GetAddons(t *testing.T) {
 func TestStage_GetAddon(t *testing.T) {
 	type fields struct {
 		Name       string
-		Properties map[string]string
+		Properties map[string]any
 		Actions    Actions
 		Clusters   map[string]*Cluster
 		Addons     map[string]*ClusterAddon
@@ -479,7 +483,7 @@ func TestStage_GetAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 				},
 			},
@@ -487,7 +491,7 @@ func TestStage_GetAddon(t *testing.T) {
 				name: "addon1",
 			},
 			want: &ClusterAddon{
-				Enabled: true,
+				Enabled: boolPtr(true),
 			},
 		},
 		{
@@ -495,10 +499,10 @@ func TestStage_GetAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -506,7 +510,7 @@ func TestStage_GetAddon(t *testing.T) {
 				name: "addon1",
 			},
 			want: &ClusterAddon{
-				Enabled: true,
+				Enabled: boolPtr(true),
 			},
 		},
 		{
@@ -514,10 +518,10 @@ func TestStage_GetAddon(t *testing.T) {
 			fields: fields{
 				Addons: map[string]*ClusterAddon{
 					"addon1": {
-						Enabled: true,
+						Enabled: boolPtr(true),
 					},
 					"addon2": {
-						Enabled: false,
+						Enabled: boolPtr(false),
 					},
 				},
 			},
@@ -546,7 +550,7 @@ func TestStage_GetAddon(t *testing.T) {
 func TestStage_GetCluster(t *testing.T) {
 	type fields struct {
 		Name       string
-		Properties map[string]string
+		Properties map[string]any
 		Actions    Actions
 		Clusters   map[string]*Cluster
 		Addons     map[string]*ClusterAddon
@@ -638,3 +642,98 @@ func TestStage_GetCluster(t *testing.T) {
 		})
 	}
 }
+
+func TestStage_Render(t *testing.T) {
+	type args struct {
+		templates map[string]string
+		env       string
+		stage     string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantFiles map[string]string
+		wantErr   bool
+	}{
+		{
+			name: "renders stage scoped templates only",
+			args: args{
+				templates: map[string]string{
+					"stage/manifest.yaml":   "name: stage\nscope: stage\nfiles:\n  - appset.yaml\n",
+					"stage/appset.yaml":     "{{ range clustersInStage .Environment .Stage }}{{ .Name }}:{{ .Properties.key }},{{ end }}",
+					"cluster/manifest.yaml": "name: cluster\nfiles:\n  - values.yaml\n",
+					"cluster/values.yaml":   "cluster: {{ .ClusterName }}",
+				},
+				env:   "env1",
+				stage: "stage1",
+			},
+			wantFiles: map[string]string{
+				"env1/stage1/stage/appset.yaml": "cluster1:value,cluster2:value,",
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templateDir := t.TempDir()
+			for name, content := range tt.args.templates {
+				err := os.MkdirAll(filepath.Dir(filepath.Join(templateDir, name)), 0775)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = os.WriteFile(filepath.Join(templateDir, name), []byte(content), 0664)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			config := &ProjectConfig{
+				BasePath:         t.TempDir(),
+				TemplateBasePath: templateDir,
+				Environments: map[string]*Environment{
+					tt.args.env: {
+						Stages: map[string]*Stage{
+							tt.args.stage: {
+								Properties: map[string]any{"key": "value"},
+								Clusters: map[string]*Cluster{
+									"cluster1": {},
+									"cluster2": {},
+								},
+							},
+						},
+					},
+				},
+			}
+
+			_, err := config.GetStage(tt.args.env, tt.args.stage).Render(config, tt.args.env)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Stage.Render() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			gotFiles := map[string]string{}
+			err = filepath.WalkDir(config.BasePath, func(fpath string, d os.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+				bts, err := os.ReadFile(fpath)
+				if err != nil {
+					return err
+				}
+				rel, err := filepath.Rel(config.BasePath, fpath)
+				if err != nil {
+					return err
+				}
+				gotFiles[rel] = string(bts)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tt.wantFiles, gotFiles); diff != "" {
+				t.Errorf("Stage.Render() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
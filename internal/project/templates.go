@@ -0,0 +1,8 @@
+package project
+
+type TemplateHandler interface {
+	// GetTemplates returns the selected base templates, nil means the selection is inherited
+	GetTemplates() []string
+	// SetTemplates sets the selected base templates, nil means the selection is inherited
+	SetTemplates(templates []string)
+}
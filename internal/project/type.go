@@ -1,6 +1,10 @@
 package project
 
 import (
+	"crypto/rsa"
+
+	"github.com/google/cel-go/cel"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/secret"
 	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
 	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/utils"
 )
@@ -13,11 +17,37 @@ type Addon struct {
 }
 
 type ProjectConfig struct {
-	BasePath         string                               `json:"basePath"`
-	TemplateBasePath string                               `json:"templateBasePath"`
-	Addons           map[string]Addon                     `json:"addons"`
-	ParsedAddons     map[string]template.TemplateManifest `json:"-"`
-	Environments     map[string]*Environment              `json:"environments"`
+	BasePath         string `json:"basePath"`
+	TemplateBasePath string `json:"templateBasePath"`
+	// HelpersPath is the location of a directory containing files with define blocks
+	// that are available to all base templates and addon files
+	HelpersPath string `json:"helpersPath,omitempty"`
+	// PropertySchema defines the properties that can be set on environments, stages and clusters
+	// Properties that are not part of the schema can still be set, but are not validated
+	PropertySchema map[string]PropertyDefinition `json:"propertySchema,omitempty"`
+	// SecretKeyFile is the location of the key file used to encrypt and decrypt secret properties
+	// The OGC_SECRET_KEY and OGC_SECRET_KEY_FILE environment variables take precedence
+	SecretKeyFile string `json:"secretKeyFile,omitempty"`
+	// SchemaPath is the location of a directory containing offline Kubernetes OpenAPI or JSON schemas
+	// The rendered resources are validated against them
+	SchemaPath string `json:"schemaPath,omitempty"`
+	// Naming restricts the names of the environments, stages and clusters
+	Naming *NamingPolicy `json:"naming,omitempty"`
+	// Policies define rules for the addons and properties of the clusters
+	Policies []Policy `json:"policies,omitempty"`
+	// KustomizeBuild enables the verification of the rendered cluster directories with kustomize build
+	KustomizeBuild *KustomizeBuild                      `json:"kustomizeBuild,omitempty"`
+	Addons         map[string]Addon                     `json:"addons"`
+	ParsedAddons   map[string]template.TemplateManifest `json:"-"`
+	Environments   map[string]*Environment              `json:"environments"`
+
+	secretKey *secret.Key
+	// sealingKeys caches the sealed secrets certificates by their location
+	sealingKeys map[string]*rsa.PublicKey
+	// validator caches the compiled schemas of the schema path
+	validator *template.Validator
+	// policyPrograms caches the compiled policy expressions
+	policyPrograms map[string]cel.Program
 }
 
 // HasCluster checks if a cluster exists in the given environment and stage
@@ -38,9 +68,56 @@ func (p *ProjectConfig) DeleteCluster(env, stage, cluster string) {
 	delete(p.GetStage(env, stage).Clusters, cluster)
 }
 
-// EnvStageProperty merges the properties of the environment and stage and returns them as a map
-func (pc *ProjectConfig) EnvStageProperty(environment, stage string) map[string]string {
-	return utils.MergeMaps(pc.GetEnvironment(environment).Properties, pc.GetStage(environment, stage).Properties)
+// EnvStageProperty merges the property defaults of the project with the properties of the environment and stage and returns them as a map
+func (pc *ProjectConfig) EnvStageProperty(environment, stage string) map[string]any {
+	return pc.PropertyLayersAt(environment, stage).Merge(pc.PropertyDefinitions())
+}
+
+// ClusterData returns all clusters of the project with their merged properties and enabled addons
+// The values of secret properties are masked, so templates cannot read the secrets of other clusters
+// The clusters are sorted by environment, stage and name
+func (p *ProjectConfig) ClusterData() ([]template.ClusterData, error) {
+	clusters := []template.ClusterData{}
+	for _, envName := range utils.SortStringSlice(utils.MapKeysToList(p.Environments)) {
+		for _, stageName := range utils.SortStringSlice(utils.MapKeysToList(p.GetEnvironment(envName).Stages)) {
+			stage := p.GetStage(envName, stageName)
+			for _, clusterName := range utils.SortStringSlice(utils.MapKeysToList(stage.Clusters)) {
+				// the name is taken from the map key without modifying the cluster of the project
+				cluster := *stage.GetCluster(clusterName)
+				cluster.Name = clusterName
+				properties, err := cluster.maskedProperties(p, envName, stageName)
+				if err != nil {
+					return nil, err
+				}
+				addonData, err := cluster.addonData(p, envName, stageName, properties)
+				if err != nil {
+					return nil, err
+				}
+				addons := map[string]template.AddonData{}
+				for addonName, addon := range addonData {
+					if !addon.Enabled {
+						continue
+					}
+					addon.Properties = maskSecretValues(p.ParsedAddons[addonName].Properties, addon.Properties)
+					addons[addonName] = addon
+				}
+				clusters = append(clusters, template.ClusterData{
+					Environment: envName,
+					Stage:       stageName,
+					Name:        clusterName,
+					Labels:      cluster.Labels,
+					Properties:  properties,
+					Addons:      addons,
+				})
+			}
+		}
+	}
+	return clusters, nil
+}
+
+// clusterSource returns a source that loads the cluster data of the project when a template uses it
+func (p *ProjectConfig) clusterSource() *template.ClusterSource {
+	return template.NewClusterSource(p.ClusterData)
 }
 
 // AddonGroups returns a list of addon groups that have been defined in the addons
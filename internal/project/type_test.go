@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/secret"
 	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
 )
 
@@ -117,7 +118,7 @@ func TestProjectConfig_GetCluster(t *testing.T) {
 								Clusters: map[string]*Cluster{
 									"cluster1": {
 										Name: "cluster1",
-										Properties: map[string]string{
+										Properties: map[string]any{
 											"key": "value",
 										},
 									},
@@ -134,7 +135,7 @@ func TestProjectConfig_GetCluster(t *testing.T) {
 			},
 			want: &Cluster{
 				Name: "cluster1",
-				Properties: map[string]string{
+				Properties: map[string]any{
 					"key": "value",
 				},
 			},
@@ -217,14 +218,14 @@ func TestProjectConfig_SetCluster(t *testing.T) {
 				stage: "stage1",
 				cluster: &Cluster{
 					Name: "cluster1",
-					Properties: map[string]string{
+					Properties: map[string]any{
 						"key": "value",
 					},
 				},
 			},
 			want: &Cluster{
 				Name: "cluster1",
-				Properties: map[string]string{
+				Properties: map[string]any{
 					"key": "value",
 				},
 			},
@@ -352,19 +353,19 @@ func TestProjectConfig_EnvStageProperty(t *testing.T) {
 		name   string
 		fields fields
 		args   args
-		want   map[string]string
+		want   map[string]any
 	}{
 		{
 			name: "should merge the properties of the environment and stage",
 			fields: fields{
 				Environments: map[string]*Environment{
 					"env1": {
-						Properties: map[string]string{
+						Properties: map[string]any{
 							"key1": "value1",
 						},
 						Stages: map[string]*Stage{
 							"stage1": {
-								Properties: map[string]string{
+								Properties: map[string]any{
 									"key2": "value2",
 								},
 							},
@@ -376,7 +377,7 @@ func TestProjectConfig_EnvStageProperty(t *testing.T) {
 				environment: "env1",
 				stage:       "stage1",
 			},
-			want: map[string]string{
+			want: map[string]any{
 				"key1": "value1",
 				"key2": "value2",
 			},
@@ -396,14 +397,14 @@ func TestProjectConfig_EnvStageProperty(t *testing.T) {
 				environment: "env1",
 				stage:       "stage1",
 			},
-			want: map[string]string{},
+			want: map[string]any{},
 		},
 		{
 			name: "should return the properties of the environment if no stage properties are defined",
 			fields: fields{
 				Environments: map[string]*Environment{
 					"env1": {
-						Properties: map[string]string{
+						Properties: map[string]any{
 							"key1": "value1",
 						},
 						Stages: map[string]*Stage{
@@ -416,7 +417,7 @@ func TestProjectConfig_EnvStageProperty(t *testing.T) {
 				environment: "env1",
 				stage:       "stage1",
 			},
-			want: map[string]string{
+			want: map[string]any{
 				"key1": "value1",
 			},
 		},
@@ -427,7 +428,7 @@ func TestProjectConfig_EnvStageProperty(t *testing.T) {
 					"env1": {
 						Stages: map[string]*Stage{
 							"stage1": {
-								Properties: map[string]string{
+								Properties: map[string]any{
 									"key2": "value2",
 								},
 							},
@@ -439,7 +440,7 @@ func TestProjectConfig_EnvStageProperty(t *testing.T) {
 				environment: "env1",
 				stage:       "stage1",
 			},
-			want: map[string]string{
+			want: map[string]any{
 				"key2": "value2",
 			},
 		},
@@ -458,7 +459,7 @@ func TestProjectConfig_EnvStageProperty(t *testing.T) {
 				environment: "env1",
 				stage:       "stage1",
 			},
-			want: map[string]string{},
+			want: map[string]any{},
 		},
 	}
 	for _, tt := range tests {
@@ -562,3 +563,194 @@ func TestProjectConfig_AddonGroups(t *testing.T) {
 		})
 	}
 }
+
+func TestProjectConfig_ClusterData(t *testing.T) {
+	type fields struct {
+		PropertySchema map[string]PropertyDefinition
+		Addons         map[string]Addon
+		ParsedAddons   map[string]template.TemplateManifest
+		Environments   map[string]*Environment
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   []template.ClusterData
+	}{
+		{
+			name: "should return sorted clusters with merged properties and enabled addons",
+			fields: fields{
+				Addons: map[string]Addon{
+					"addon1": {Group: "group1"},
+					"addon2": {Group: "group2"},
+				},
+				ParsedAddons: map[string]template.TemplateManifest{
+					"addon1": {
+						Group: "group1",
+						Properties: map[string]template.Property{
+							"property1": {
+								Default: "default",
+								Type:    template.PropertyTypeString,
+							},
+						},
+					},
+					"addon2": {Group: "group2"},
+				},
+				Environments: map[string]*Environment{
+					"env1": {
+						Properties: map[string]any{
+							"key1": "env",
+							"key2": "env",
+						},
+						Stages: map[string]*Stage{
+							"stage1": {
+								Properties: map[string]any{
+									"key2": "stage",
+								},
+								Clusters: map[string]*Cluster{
+									"spoke": {
+										Addons: map[string]*ClusterAddon{
+											"addon1": {Enabled: boolPtr(true)},
+											"addon2": {Enabled: boolPtr(false)},
+										},
+										Properties: map[string]any{
+											"key3": "cluster",
+										},
+									},
+									"hub": {},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: []template.ClusterData{
+				{
+					Environment: "env1",
+					Stage:       "stage1",
+					Name:        "hub",
+					Properties: map[string]any{
+						"key1": "env",
+						"key2": "stage",
+					},
+					Addons: map[string]template.AddonData{},
+				},
+				{
+					Environment: "env1",
+					Stage:       "stage1",
+					Name:        "spoke",
+					Properties: map[string]any{
+						"key1": "env",
+						"key2": "stage",
+						"key3": "cluster",
+					},
+					Addons: map[string]template.AddonData{
+						"addon1": {
+							Enabled:    true,
+							Group:      "group1",
+							Properties: map[string]any{"property1": "default"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "should mask the secrets of the clusters and addons",
+			fields: fields{
+				PropertySchema: map[string]PropertyDefinition{
+					"token": {Property: template.Property{Type: template.PropertyTypeSecret}},
+					"url":   {Property: template.Property{Type: template.PropertyTypeString}},
+				},
+				Addons: map[string]Addon{
+					"addon1": {Group: "group1"},
+				},
+				ParsedAddons: map[string]template.TemplateManifest{
+					"addon1": {
+						Group: "group1",
+						Properties: map[string]template.Property{
+							"password": {Type: template.PropertyTypeSecret},
+						},
+					},
+				},
+				Environments: map[string]*Environment{
+					"env1": {
+						Stages: map[string]*Stage{
+							"stage1": {
+								Clusters: map[string]*Cluster{
+									"hub": {
+										Addons: map[string]*ClusterAddon{
+											"addon1": {Enabled: boolPtr(true), Properties: map[string]any{"password": "plain"}},
+										},
+										Properties: map[string]any{
+											"token": "plain",
+											"url":   "https://${properties.token}@hub",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: []template.ClusterData{
+				{
+					Environment: "env1",
+					Stage:       "stage1",
+					Name:        "hub",
+					Properties: map[string]any{
+						"token": secret.Mask,
+						"url":   "https://" + secret.Mask + "@hub",
+					},
+					Addons: map[string]template.AddonData{
+						"addon1": {
+							Enabled:    true,
+							Group:      "group1",
+							Properties: map[string]any{"password": secret.Mask},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "should return an empty list if no clusters are defined",
+			fields: fields{
+				Environments: map[string]*Environment{
+					"env1": {
+						Stages: map[string]*Stage{
+							"stage1": {},
+						},
+					},
+				},
+			},
+			want: []template.ClusterData{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pc := &ProjectConfig{
+				PropertySchema: tt.fields.PropertySchema,
+				Addons:         tt.fields.Addons,
+				ParsedAddons:   tt.fields.ParsedAddons,
+				Environments:   tt.fields.Environments,
+			}
+
+			got, err := pc.ClusterData()
+			if err != nil {
+				t.Errorf("ProjectConfig.ClusterData() error = %v", err)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("ProjectConfig.ClusterData() mismatch (-got +want):\n%s", diff)
+				return
+			}
+			for _, env := range pc.Environments {
+				for _, stage := range env.Stages {
+					for _, cluster := range stage.Clusters {
+						if cluster.Name != "" {
+							t.Errorf("ProjectConfig.ClusterData() must not modify the cluster %s", cluster.Name)
+						}
+					}
+				}
+			}
+		})
+	}
+}
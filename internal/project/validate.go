@@ -0,0 +1,18 @@
+package project
+
+import (
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/template"
+)
+
+// ValidateRenderedFiles checks that the rendered yaml files are well-formed
+// If a schema path is configured, the rendered resources are validated against its schemas
+func (p *ProjectConfig) ValidateRenderedFiles(files []template.RenderedFile) error {
+	if p.validator == nil {
+		validator, err := template.NewValidator(p.SchemaPath)
+		if err != nil {
+			return err
+		}
+		p.validator = validator
+	}
+	return p.validator.Validate(files)
+}
@@ -0,0 +1,119 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// SealingScope defines to which namespace and name a sealed value is bound
+// See https://github.com/bitnami-labs/sealed-secrets#scopes
+type SealingScope string
+
+const (
+	// SealingScopeStrict binds the sealed value to the namespace and name of the secret
+	SealingScopeStrict SealingScope = "strict"
+	// SealingScopeNamespaceWide binds the sealed value to the namespace of the secret
+	SealingScopeNamespaceWide SealingScope = "namespace-wide"
+	// SealingScopeClusterWide allows to unseal the value in any namespace and with any name
+	SealingScopeClusterWide SealingScope = "cluster-wide"
+
+	sessionKeyLength = 32
+)
+
+// LoadSealingCertificate reads the PEM encoded certificate of the sealed-secrets controller and returns its public key
+func LoadSealingCertificate(path string) (*rsa.PublicKey, error) {
+	bts, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sealed secrets certificate: %w", err)
+	}
+	return ParseSealingCertificate(bts)
+}
+
+// ParseSealingCertificate parses the PEM encoded certificate of the sealed-secrets controller and returns its public key
+func ParseSealingCertificate(bts []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(bts)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("sealed secrets certificate must be a PEM encoded certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sealed secrets certificate: %w", err)
+	}
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("sealed secrets certificate must contain a RSA public key")
+	}
+	return key, nil
+}
+
+// Seal encrypts the value for the sealed-secrets controller and returns it base64 encoded
+// The result is equal to `kubeseal --raw --cert`, it can be used as value of the encryptedData of a SealedSecret
+func Seal(key *rsa.PublicKey, scope SealingScope, namespace, name, value string) (string, error) {
+	label, err := sealingLabel(scope, namespace, name)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := hybridEncrypt(key, []byte(value), label)
+	if err != nil {
+		return "", fmt.Errorf("failed to seal value: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// sealingLabel returns the label the value is bound to, the controller uses the same label to decrypt the value
+func sealingLabel(scope SealingScope, namespace, name string) ([]byte, error) {
+	switch scope {
+	case SealingScopeStrict:
+		if namespace == "" || name == "" {
+			return nil, errors.New("namespace and name are required for strict scoped secrets")
+		}
+		return []byte(namespace + "/" + name), nil
+	case SealingScopeNamespaceWide:
+		if namespace == "" {
+			return nil, errors.New("namespace is required for namespace-wide scoped secrets")
+		}
+		return []byte(namespace), nil
+	case SealingScopeClusterWide:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown sealing scope %s", scope)
+}
+
+// hybridEncrypt encrypts the plain text with a random AES-GCM session key, which itself is encrypted with RSA-OAEP
+// The layout matches the one of the sealed-secrets controller: <2 byte length of the encrypted session key><encrypted session key><encrypted plain text>
+func hybridEncrypt(key *rsa.PublicKey, plain, label []byte) ([]byte, error) {
+	sessionKey := make([]byte, sessionKeyLength)
+	_, err := rand.Read(sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, key, sessionKey, label)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := make([]byte, 2, 2+len(encryptedKey)+len(plain)+aead.Overhead())
+	binary.BigEndian.PutUint16(sealed, uint16(len(encryptedKey)))
+	sealed = append(sealed, encryptedKey...)
+	// the session key is only used once, so a zero nonce is fine
+	nonce := make([]byte, aead.NonceSize())
+	return aead.Seal(sealed, nonce, plain, nil), nil
+}
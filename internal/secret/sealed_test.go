@@ -0,0 +1,164 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// newSealingCertificate returns a self signed certificate and its private key, like the one of the sealed-secrets controller
+func newSealingCertificate(t *testing.T) ([]byte, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sealed-secret"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), key
+}
+
+// unseal decrypts the value the same way the sealed-secrets controller does
+func unseal(key *rsa.PrivateKey, value string, label []byte) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+	keyLength := int(binary.BigEndian.Uint16(sealed))
+	sessionKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, key, sealed[2:2+keyLength], label)
+	if err != nil {
+		return "", err
+	}
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		return "", err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	plain, err := aead.Open(nil, make([]byte, aead.NonceSize()), sealed[2+keyLength:], nil)
+	return string(plain), err
+}
+
+func TestSeal(t *testing.T) {
+	cert, privateKey := newSealingCertificate(t)
+	publicKey, err := ParseSealingCertificate(cert)
+	if err != nil {
+		t.Fatalf("ParseSealingCertificate() error = %v", err)
+	}
+	tests := []struct {
+		name      string
+		scope     SealingScope
+		namespace string
+		secret    string
+		label     string
+		wantErr   bool
+	}{
+		{
+			name:      "strict",
+			scope:     SealingScopeStrict,
+			namespace: "vault",
+			secret:    "unseal",
+			label:     "vault/unseal",
+		},
+		{
+			name:      "namespace-wide",
+			scope:     SealingScopeNamespaceWide,
+			namespace: "vault",
+			label:     "vault",
+		},
+		{
+			name:  "cluster-wide",
+			scope: SealingScopeClusterWide,
+		},
+		{
+			name:      "strict without name",
+			scope:     SealingScopeStrict,
+			namespace: "vault",
+			wantErr:   true,
+		},
+		{
+			name:    "unknown scope",
+			scope:   "global",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Seal(publicKey, tt.scope, tt.namespace, tt.secret, "s3cr3t")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Seal() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			plain, err := unseal(privateKey, got, []byte(tt.label))
+			if err != nil {
+				t.Fatalf("failed to unseal value: %v", err)
+			}
+			if plain != "s3cr3t" {
+				t.Errorf("Seal() unsealed = %s, want %s", plain, "s3cr3t")
+			}
+			_, err = unseal(privateKey, got, []byte("other/label"))
+			if err == nil {
+				t.Errorf("Seal() value must be bound to the label %q", tt.label)
+			}
+		})
+	}
+}
+
+func TestParseSealingCertificate(t *testing.T) {
+	cert, key := newSealingCertificate(t)
+	tests := []struct {
+		name    string
+		pem     []byte
+		wantErr bool
+	}{
+		{
+			name: "certificate",
+			pem:  cert,
+		},
+		{
+			name:    "private key",
+			pem:     pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+			wantErr: true,
+		},
+		{
+			name:    "no pem",
+			pem:     []byte("not a certificate"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSealingCertificate(tt.pem)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSealingCertificate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !got.Equal(&key.PublicKey) {
+				t.Errorf("ParseSealingCertificate() returned a different public key")
+			}
+		})
+	}
+}
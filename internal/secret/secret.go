@@ -0,0 +1,125 @@
+package secret
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+const (
+	// KeyEnvVar is the environment variable that contains the base64 encoded key
+	KeyEnvVar = "OGC_SECRET_KEY"
+	// KeyFileEnvVar is the environment variable that contains the path to the key file
+	KeyFileEnvVar = "OGC_SECRET_KEY_FILE"
+
+	// Mask replaces secret values in all outputs
+	Mask = "********"
+
+	// prefix marks encrypted values
+	prefix    = "ENC[secretbox,"
+	suffix    = "]"
+	keyLength = 32
+	nonceSize = 24
+)
+
+var (
+	// ErrNoKey is returned if no key has been configured
+	ErrNoKey = fmt.Errorf("no secret key configured, set %s, %s or secretKeyFile in the PROJECT.yaml file", KeyEnvVar, KeyFileEnvVar)
+)
+
+// Key is a symmetric key used to encrypt and decrypt secret values
+type Key [keyLength]byte
+
+// LoadKey loads the key from the OGC_SECRET_KEY or OGC_SECRET_KEY_FILE environment variables, or from the given file
+// The key must be 32 bytes, base64 encoded
+func LoadKey(file string) (*Key, error) {
+	encoded, ok := os.LookupEnv(KeyEnvVar)
+	if !ok {
+		if path, ok := os.LookupEnv(KeyFileEnvVar); ok {
+			file = path
+		}
+		if file == "" {
+			return nil, ErrNoKey
+		}
+		bts, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secret key file: %w", err)
+		}
+		encoded = string(bts)
+	}
+	return ParseKey(encoded)
+}
+
+// ParseKey parses the base64 encoded key
+func ParseKey(encoded string) (*Key, error) {
+	bts, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode secret key: %w", err)
+	}
+	if len(bts) != keyLength {
+		return nil, fmt.Errorf("secret key must be %d bytes long, got %d", keyLength, len(bts))
+	}
+	key := &Key{}
+	copy(key[:], bts)
+	return key, nil
+}
+
+// GenerateKey returns a new random key
+func GenerateKey() (*Key, error) {
+	key := &Key{}
+	_, err := rand.Read(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// String returns the base64 encoded key
+func (k *Key) String() string {
+	return base64.StdEncoding.EncodeToString(k[:])
+}
+
+// IsEncrypted checks if the value has been encrypted
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix) && strings.HasSuffix(value, suffix)
+}
+
+// Encrypt encrypts the plain text, values that are already encrypted are returned as they are
+func (k *Key) Encrypt(plain string) (string, error) {
+	if IsEncrypted(plain) {
+		return plain, nil
+	}
+	nonce := [nonceSize]byte{}
+	_, err := rand.Read(nonce[:])
+	if err != nil {
+		return "", err
+	}
+	sealed := secretbox.Seal(nonce[:], []byte(plain), &nonce, (*[keyLength]byte)(k))
+	return prefix + base64.StdEncoding.EncodeToString(sealed) + suffix, nil
+}
+
+// Decrypt decrypts the encrypted value, plain values are returned as they are
+func (k *Key) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSuffix(strings.TrimPrefix(value, prefix), suffix))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode secret: %w", err)
+	}
+	if len(sealed) < nonceSize {
+		return "", errors.New("secret is too short")
+	}
+	nonce := [nonceSize]byte{}
+	copy(nonce[:], sealed[:nonceSize])
+	plain, ok := secretbox.Open(nil, sealed[nonceSize:], &nonce, (*[keyLength]byte)(k))
+	if !ok {
+		return "", errors.New("failed to decrypt secret, the key does not match")
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,147 @@
+package secret
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKey_EncryptDecrypt(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		plain   string
+		key     *Key
+		wantErr bool
+	}{
+		{
+			name:  "round trip",
+			plain: "s3cr3t",
+			key:   key,
+		},
+		{
+			name:  "empty value",
+			plain: "",
+			key:   key,
+		},
+		{
+			name:    "wrong key",
+			plain:   "s3cr3t",
+			key:     otherKey,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := key.Encrypt(tt.plain)
+			if err != nil {
+				t.Fatalf("Key.Encrypt() error = %v", err)
+			}
+			if !IsEncrypted(encrypted) || strings.Contains(encrypted, tt.plain) && tt.plain != "" {
+				t.Fatalf("Key.Encrypt() = %s, expected an encrypted value", encrypted)
+			}
+			again, err := key.Encrypt(encrypted)
+			if err != nil || again != encrypted {
+				t.Fatalf("Key.Encrypt() must not encrypt encrypted values twice, got %s, %v", again, err)
+			}
+			got, err := tt.key.Decrypt(encrypted)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Key.Decrypt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.plain {
+				t.Errorf("Key.Decrypt() = %s, want %s", got, tt.plain)
+			}
+		})
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		encoded string
+		wantErr bool
+	}{
+		{
+			name:    "valid key",
+			encoded: key.String(),
+		},
+		{
+			name:    "trailing newline",
+			encoded: key.String() + "\n",
+		},
+		{
+			name:    "invalid base64",
+			encoded: "not base64!",
+			wantErr: true,
+		},
+		{
+			name:    "too short",
+			encoded: "c2hvcnQ=",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseKey(tt.encoded)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && *got != *key {
+				t.Errorf("ParseKey() = %s, want %s", got, key)
+			}
+		})
+	}
+}
+
+func TestLoadKey(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "key")
+	err = os.WriteFile(file, []byte(key.String()+"\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("no key", func(t *testing.T) {
+		_, err := LoadKey("")
+		if err != ErrNoKey {
+			t.Errorf("LoadKey() error = %v, want %v", err, ErrNoKey)
+		}
+	})
+	t.Run("from file", func(t *testing.T) {
+		got, err := LoadKey(file)
+		if err != nil || *got != *key {
+			t.Errorf("LoadKey() = %v, %v", got, err)
+		}
+	})
+	t.Run("from file env var", func(t *testing.T) {
+		t.Setenv(KeyFileEnvVar, file)
+		got, err := LoadKey("does-not-exist")
+		if err != nil || *got != *key {
+			t.Errorf("LoadKey() = %v, %v", got, err)
+		}
+	})
+	t.Run("env var takes precedence", func(t *testing.T) {
+		t.Setenv(KeyEnvVar, key.String())
+		got, err := LoadKey("does-not-exist")
+		if err != nil || *got != *key {
+			t.Errorf("LoadKey() = %v, %v", got, err)
+		}
+	})
+}
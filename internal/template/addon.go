@@ -1,6 +1,7 @@
 package template
 
 import (
+	"crypto/rsa"
 	"fmt"
 	"io/fs"
 	"os"
@@ -21,13 +22,22 @@ type AddonTemplateCarrier struct {
 	Group string
 	// Files represents a map of file names and their respective templates
 	Files map[string]*template.Template
+	// Sources represents a map of file names and the location of the file variant they have been loaded from
+	Sources map[string]string
+	// Overrides represents a map of file names that have been loaded from an environment or stage specific override
+	Overrides map[string]bool
 }
 
-func LoadTemplatesFromAddonManifest(source TemplateManifest) (*AddonTemplateCarrier, error) {
+// LoadTemplatesFromAddonManifest loads all files of the addon that are referenced in the manifest as templates
+// The define blocks of the helpers template are available to all addon files, helpers may be nil
+// Files in <addonPath>/overrides/<env>/<stage> and <addonPath>/overrides/<env> take precedence over the base files
+func LoadTemplatesFromAddonManifest(source TemplateManifest, helpers *template.Template, env, stage string) (*AddonTemplateCarrier, error) {
 	template := &AddonTemplateCarrier{
-		Name:  source.Name,
-		Group: source.Group,
-		Files: map[string]*template.Template{},
+		Name:      source.Name,
+		Group:     source.Group,
+		Files:     map[string]*template.Template{},
+		Sources:   map[string]string{},
+		Overrides: map[string]bool{},
 	}
 	err := filepath.WalkDir(source.BasePath, func(fpath string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -35,6 +45,10 @@ func LoadTemplatesFromAddonManifest(source TemplateManifest) (*AddonTemplateCarr
 		}
 
 		if d.IsDir() {
+			if isOverridesDirectory(source.BasePath, fpath) {
+				// overrides are resolved per file
+				return filepath.SkipDir
+			}
 			// skip directories
 			return nil
 		}
@@ -59,12 +73,15 @@ func LoadTemplatesFromAddonManifest(source TemplateManifest) (*AddonTemplateCarr
 			return nil
 		}
 
-		tmpl, err := parseFile(fpath)
+		fpath, isOverride := resolveOverride(source.BasePath, fileName, env, stage)
+		tmpl, err := parseFile(fpath, helpers)
 		if err != nil {
 			return err
 		}
 
 		template.Files[fileName] = tmpl
+		template.Sources[fileName] = fpath
+		template.Overrides[fileName] = isOverride
 		return nil
 	})
 	if err != nil {
@@ -77,41 +94,57 @@ type AddonTemplateData struct {
 	Environment       string
 	Stage             string
 	Cluster           string
-	ClusterProperties map[string]string
+	ClusterProperties map[string]any
 	Properties        map[string]any
+	// ClusterSource provides the clusters of the project, may be nil
+	ClusterSource *ClusterSource
+	// SealingKey is the public key of the sealed-secrets controller of the environment, may be nil
+	SealingKey *rsa.PublicKey
+}
+
+// Clusters returns all clusters of the project, they are loaded on first use
+func (a AddonTemplateData) Clusters() ([]ClusterData, error) {
+	return a.ClusterSource.Clusters()
 }
 
-func (a AddonTemplateCarrier) Render(basePath string, properties AddonTemplateData) error {
+// Render renders all addon files and returns the rendered files
+func (a AddonTemplateCarrier) Render(basePath string, properties AddonTemplateData) ([]RenderedFile, error) {
 	if len(a.Files) == 0 {
 		// nothing to render
-		return nil
+		return nil, nil
 	}
 	originPath := path.Join(basePath, properties.Environment, properties.Stage, properties.Cluster, a.Group, a.Name)
 	err := os.MkdirAll(originPath, 0775)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	rendered := []RenderedFile{}
 	for fileName, tmpl := range a.Files {
 		baseFileName := filepath.Base(fileName)
 		if len(fileName) > len(baseFileName) {
 			// create the directory structure
 			err := os.MkdirAll(path.Join(originPath, strings.TrimSuffix(fileName, baseFileName)), 0775)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 
 		// create the file and render the template
-		file, err := os.OpenFile(path.Join(originPath, fileName), os.O_CREATE|os.O_WRONLY, 0664)
+		file, err := os.OpenFile(path.Join(originPath, fileName), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0664)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		defer file.Close()
 
-		err = tmpl.Execute(file, properties)
+		err = tmpl.Funcs(clusterFuncMap(properties.ClusterSource)).Funcs(sealFuncMap(properties.SealingKey)).Execute(file, properties)
 		if err != nil {
-			return fmt.Errorf("failed to render template file %s: %w", fileName, err)
+			return nil, fmt.Errorf("failed to render template file %s: %w", fileName, err)
 		}
+		rendered = append(rendered, RenderedFile{
+			Path:     path.Join(originPath, fileName),
+			Source:   a.Sources[fileName],
+			Override: a.Overrides[fileName],
+		})
 	}
-	return nil
+	return rendered, nil
 }
@@ -0,0 +1,57 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// HasTemplatedDefault checks if the default value of the property is a template that must be evaluated with the cluster context
+func (p Property) HasTemplatedDefault() bool {
+	s, ok := p.Default.(string)
+	return ok && strings.Contains(s, "{{")
+}
+
+// RenderDefault returns the default value of the property
+// Templated defaults are evaluated with the given template data and the result is parsed according to the property type
+func (p Property) RenderDefault(td TemplateData) (any, error) {
+	if !p.HasTemplatedDefault() {
+		return p.Default, nil
+	}
+	tmpl := template.New("default")
+	tmpl, err := tmpl.Funcs(funcMap(tmpl)).Parse(p.Default.(string))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse default: %w", err)
+	}
+	buf := &bytes.Buffer{}
+	err = tmpl.Funcs(clusterFuncMap(td.ClusterSource)).Funcs(sealFuncMap(td.SealingKey)).Execute(buf, td)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render default: %w", err)
+	}
+
+	property := p
+	property.Default = nil
+	value, err := property.ParseValue(buf.String())
+	if err != nil && p.Type == PropertyTypeSecret {
+		// secret values must not be part of error messages
+		return nil, fmt.Errorf("rendered default is invalid: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("rendered default %q is invalid: %w", buf.String(), err)
+	}
+	return value, nil
+}
+
+// RenderDefaults returns the default values of the given properties, templated defaults are evaluated with the given template data
+func RenderDefaults(properties map[string]Property, td TemplateData) (map[string]any, error) {
+	defaults := make(map[string]any, len(properties))
+	for key, property := range properties {
+		value, err := property.RenderDefault(td)
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %w", key, err)
+		}
+		defaults[key] = value
+	}
+	return defaults, nil
+}
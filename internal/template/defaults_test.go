@@ -0,0 +1,80 @@
+package template
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProperty_RenderDefault(t *testing.T) {
+	td := TemplateData{
+		Environment: "dev",
+		Stage:       "test",
+		ClusterName: "hugi",
+		Properties: map[string]any{
+			"baseDomain": "example.com",
+			"replicas":   3,
+		},
+	}
+	tests := []struct {
+		name     string
+		property Property
+		want     any
+		wantErr  bool
+	}{
+		{
+			name:     "static default",
+			property: Property{Type: PropertyTypeString, Default: "monitoring"},
+			want:     "monitoring",
+		},
+		{
+			name:     "without default",
+			property: Property{Type: PropertyTypeString},
+			want:     nil,
+		},
+		{
+			name:     "templated string",
+			property: Property{Type: PropertyTypeString, Default: "monitoring.apps.{{ .ClusterName }}.{{ .Properties.baseDomain }}"},
+			want:     "monitoring.apps.hugi.example.com",
+		},
+		{
+			name:     "templated int",
+			property: Property{Type: PropertyTypeInt, Default: "{{ .Properties.replicas }}"},
+			want:     3,
+		},
+		{
+			name:     "templated value violates type",
+			property: Property{Type: PropertyTypeInt, Default: "{{ .ClusterName }}"},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid template",
+			property: Property{Type: PropertyTypeString, Default: "{{ .ClusterName "},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.property.RenderDefault(td)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Property.RenderDefault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Property.RenderDefault() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProperty_RenderDefault_secretError(t *testing.T) {
+	property := Property{Type: PropertyTypeSecret, Pattern: "^[0-9]+$", Default: "{{ .ClusterName }}-S3CRET"}
+	_, err := property.RenderDefault(TemplateData{ClusterName: "hugi"})
+	if err == nil {
+		t.Fatal("Property.RenderDefault() error = nil, want error")
+	}
+	if strings.Contains(err.Error(), "S3CRET") {
+		t.Errorf("Property.RenderDefault() error must not contain the secret: %v", err)
+	}
+}
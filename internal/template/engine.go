@@ -1,10 +1,13 @@
 package template
 
 import (
+	"crypto/rsa"
+	"fmt"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"text/template"
 )
 
@@ -19,6 +22,10 @@ type TemplateCarrier struct {
 	TemplateName string
 	FileName     string
 	Template     *template.Template
+	// Source is the location of the file variant the template has been loaded from
+	Source string
+	// Override indicates that the template has been loaded from an environment or stage specific override
+	Override bool
 }
 
 type TemplateData struct {
@@ -28,7 +35,16 @@ type TemplateData struct {
 	Stage       string
 	ClusterName string
 	Addons      map[string]AddonData
-	Properties  map[string]string
+	Properties  map[string]any
+	// ClusterSource provides the clusters of the project, may be nil
+	ClusterSource *ClusterSource
+	// SealingKey is the public key of the sealed-secrets controller of the environment, may be nil
+	SealingKey *rsa.PublicKey
+}
+
+// Clusters returns all clusters of the project, they are loaded on first use
+func (td TemplateData) Clusters() ([]ClusterData, error) {
+	return td.ClusterSource.Clusters()
 }
 
 type AddonData struct {
@@ -38,23 +54,67 @@ type AddonData struct {
 	Properties  map[string]any
 }
 
-// Render renders the template with the given carrier
-func (t Template) Render(basePath string, td TemplateData) error {
-	files, err := t.loadAsTemplate()
+// ClusterData is the read only view of a cluster of the project
+type ClusterData struct {
+	Environment string
+	Stage       string
+	Name        string
+	Labels      map[string]string
+	// Properties contains the cluster properties merged with the environment and stage properties
+	Properties map[string]any
+	// Addons contains the enabled addons of the cluster
+	Addons map[string]AddonData
+}
+
+// ClusterSource loads the clusters of the project when a template uses them for the first time
+// Templates that do not access other clusters therefore do not depend on their properties
+type ClusterSource struct {
+	load     func() ([]ClusterData, error)
+	once     sync.Once
+	clusters []ClusterData
+	err      error
+}
+
+// NewClusterSource returns a source that loads the clusters at most once with the given function
+func NewClusterSource(load func() ([]ClusterData, error)) *ClusterSource {
+	return &ClusterSource{load: load}
+}
+
+// Clusters returns the loaded clusters, a nil source does not provide any clusters
+func (s *ClusterSource) Clusters() ([]ClusterData, error) {
+	if s == nil {
+		return nil, nil
+	}
+	s.once.Do(func() {
+		s.clusters, s.err = s.load()
+		if s.err != nil {
+			s.err = fmt.Errorf("failed to load the clusters of the project: %w", s.err)
+		}
+	})
+	return s.clusters, s.err
+}
+
+// Render renders the template with the given carrier and returns the rendered files
+// The define blocks of the helpers template are available to all template files, helpers may be nil
+// Files in <templatePath>/overrides/<env>/<stage> and <templatePath>/overrides/<env> take precedence over the base files
+func (t Template) Render(basePath string, helpers *template.Template, td TemplateData) ([]RenderedFile, error) {
+	files, err := t.loadAsTemplate(helpers, td.Environment, td.Stage)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	rendered := []RenderedFile{}
 	for _, file := range files {
-		err = renderTemplate(basePath, td, file)
+		rf, err := renderTemplate(basePath, td, file)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		rendered = append(rendered, *rf)
 	}
-	return nil
+	return rendered, nil
 }
 
 // loadAsTemplate loads the template files as a template
-func (t Template) loadAsTemplate() ([]TemplateCarrier, error) {
+func (t Template) loadAsTemplate(helpers *template.Template, env, stage string) ([]TemplateCarrier, error) {
 	files := []TemplateCarrier{}
 	for _, file := range t.TemplateManifest.Files {
 		fpath := path.Join(t.Path, file)
@@ -66,7 +126,8 @@ func (t Template) loadAsTemplate() ([]TemplateCarrier, error) {
 
 		if !finfo.IsDir() {
 			// is a file
-			tmpl, err := parseFile(fpath)
+			source, isOverride := resolveOverride(t.Path, file, env, stage)
+			tmpl, err := parseFile(source, helpers)
 			if err != nil {
 				return nil, err
 			}
@@ -74,6 +135,8 @@ func (t Template) loadAsTemplate() ([]TemplateCarrier, error) {
 				TemplateName: t.TemplateManifest.Name,
 				FileName:     file,
 				Template:     tmpl,
+				Source:       source,
+				Override:     isOverride,
 			}
 			files = append(files, tc)
 			continue
@@ -86,17 +149,25 @@ func (t Template) loadAsTemplate() ([]TemplateCarrier, error) {
 				return err
 			}
 			if d.IsDir() {
+				if isOverridesDirectory(t.Path, fpath) {
+					// overrides are resolved per file
+					return filepath.SkipDir
+				}
 				// we don't care about directories
 				return nil
 			}
-			tmpl, err := parseFile(fpath)
+			fileName := strings.TrimPrefix(fpath, t.Path)
+			source, isOverride := resolveOverride(t.Path, fileName, env, stage)
+			tmpl, err := parseFile(source, helpers)
 			if err != nil {
 				return err
 			}
 			tc := TemplateCarrier{
 				TemplateName: t.TemplateManifest.Name,
-				FileName:     strings.TrimPrefix(fpath, t.Path),
+				FileName:     fileName,
 				Template:     tmpl,
+				Source:       source,
+				Override:     isOverride,
 			}
 			files = append(files, tc)
 			return nil
@@ -108,13 +179,22 @@ func (t Template) loadAsTemplate() ([]TemplateCarrier, error) {
 	return files, nil
 }
 
-func parseFile(fpath string) (*template.Template, error) {
+// parseFile parses the file at the given path as template
+// If helpers is not nil, the file is parsed into a copy of it, so that the define blocks of the helpers can be included
+func parseFile(fpath string, helpers *template.Template) (*template.Template, error) {
 	bts, err := os.ReadFile(fpath)
 	if err != nil {
 		return nil, err
 	}
 	// parse the template
 	tmpl := template.New("root")
+	if helpers != nil {
+		set, err := helpers.Clone()
+		if err != nil {
+			return nil, err
+		}
+		tmpl = set.New("root")
+	}
 	tpl, err := tmpl.Funcs(funcMap(tmpl)).Parse(string(bts))
 	if err != nil {
 		return nil, err
@@ -123,7 +203,7 @@ func parseFile(fpath string) (*template.Template, error) {
 }
 
 // renderTemplate renders the template with the given carrier and writes it to the file system
-func renderTemplate(basePath string, td TemplateData, t TemplateCarrier) error {
+func renderTemplate(basePath string, td TemplateData, t TemplateCarrier) (*RenderedFile, error) {
 	dpath := path.Join(basePath, td.Environment, td.Stage, td.ClusterName, t.TemplateName)
 	if fd := path.Dir(t.FileName); fd != "." {
 		t.FileName = strings.TrimPrefix(t.FileName, fd)
@@ -131,18 +211,22 @@ func renderTemplate(basePath string, td TemplateData, t TemplateCarrier) error {
 	}
 	err := os.MkdirAll(dpath, 0755)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	file, err := os.OpenFile(path.Join(dpath, t.FileName), os.O_CREATE|os.O_WRONLY, 0644)
+	file, err := os.OpenFile(path.Join(dpath, t.FileName), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer file.Close()
 
-	err = t.Template.Execute(file, td)
+	err = t.Template.Funcs(clusterFuncMap(td.ClusterSource)).Funcs(sealFuncMap(td.SealingKey)).Execute(file, td)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return &RenderedFile{
+		Path:     path.Join(dpath, t.FileName),
+		Source:   t.Source,
+		Override: t.Override,
+	}, nil
 }
@@ -3,6 +3,7 @@ package template
 import (
 	"bytes"
 	"compress/gzip"
+	"crypto/rsa"
 	"fmt"
 	"io"
 	"path"
@@ -10,6 +11,7 @@ import (
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/secret"
 	"sigs.k8s.io/yaml"
 )
 
@@ -20,9 +22,86 @@ func funcMap(tmpl *template.Template) template.FuncMap {
 	templateFuncMap["gunzip"] = gzipDecompress
 	templateFuncMap["include"] = includeFun(tmpl, map[string]int{})
 	templateFuncMap["joinPath"] = path.Join
+	for name, fn := range clusterFuncMap(nil) {
+		templateFuncMap[name] = fn
+	}
+	for name, fn := range sealFuncMap(nil) {
+		templateFuncMap[name] = fn
+	}
 	return templateFuncMap
 }
 
+// sealFuncMap returns the functions that seal values for the sealed-secrets controller with the given public key
+// The functions must be rebound with the key of the environment before the template is executed
+func sealFuncMap(key *rsa.PublicKey) template.FuncMap {
+	seal := func(scope secret.SealingScope, namespace, name, value string) (string, error) {
+		if key == nil {
+			return "", fmt.Errorf("no sealed secrets certificate configured for the environment")
+		}
+		return secret.Seal(key, scope, namespace, name, value)
+	}
+	return template.FuncMap{
+		"sealSecret": func(namespace, name, value string) (string, error) {
+			return seal(secret.SealingScopeStrict, namespace, name, value)
+		},
+		"sealSecretNamespaceWide": func(namespace, value string) (string, error) {
+			return seal(secret.SealingScopeNamespaceWide, namespace, "", value)
+		},
+		"sealSecretClusterWide": func(value string) (string, error) {
+			return seal(secret.SealingScopeClusterWide, "", "", value)
+		},
+	}
+}
+
+// clusterFuncMap returns the functions that provide read access to the clusters of the given source
+// The functions must be rebound with the clusters of the project before the template is executed
+// The clusters are only loaded if one of the functions is called
+func clusterFuncMap(source *ClusterSource) template.FuncMap {
+	return template.FuncMap{
+		"clusters": func() ([]ClusterData, error) {
+			return source.Clusters()
+		},
+		"clustersInEnvironment": func(env string) ([]ClusterData, error) {
+			clusters, err := source.Clusters()
+			if err != nil {
+				return nil, err
+			}
+			result := []ClusterData{}
+			for _, c := range clusters {
+				if c.Environment == env {
+					result = append(result, c)
+				}
+			}
+			return result, nil
+		},
+		"clustersInStage": func(env, stage string) ([]ClusterData, error) {
+			clusters, err := source.Clusters()
+			if err != nil {
+				return nil, err
+			}
+			result := []ClusterData{}
+			for _, c := range clusters {
+				if c.Environment == env && c.Stage == stage {
+					result = append(result, c)
+				}
+			}
+			return result, nil
+		},
+		"lookupCluster": func(env, stage, name string) (*ClusterData, error) {
+			clusters, err := source.Clusters()
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range clusters {
+				if c.Environment == env && c.Stage == stage && c.Name == name {
+					return &c, nil
+				}
+			}
+			return nil, nil
+		},
+	}
+}
+
 func toYAML(v interface{}) string {
 	data, err := yaml.Marshal(v)
 	if err != nil {
@@ -1,6 +1,14 @@
 package template
 
-import "testing"
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+	"text/template"
+)
 
 func Test_toYAML(t *testing.T) {
 	type args struct {
@@ -46,3 +54,177 @@ func Test_toYAML(t *testing.T) {
 		})
 	}
 }
+
+func Test_clusterFuncMap(t *testing.T) {
+	clusters := []ClusterData{
+		{Environment: "dev", Stage: "dev", Name: "hub"},
+		{Environment: "dev", Stage: "dev", Name: "spoke1"},
+		{Environment: "dev", Stage: "prod", Name: "spoke2"},
+		{Environment: "aws", Stage: "prod", Name: "spoke3"},
+	}
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			name:     "all clusters",
+			template: `{{ range clusters }}{{ .Name }},{{ end }}`,
+			want:     "hub,spoke1,spoke2,spoke3,",
+		},
+		{
+			name:     "clusters in environment",
+			template: `{{ range clustersInEnvironment "dev" }}{{ .Name }},{{ end }}`,
+			want:     "hub,spoke1,spoke2,",
+		},
+		{
+			name:     "clusters in stage",
+			template: `{{ range clustersInStage "dev" "dev" }}{{ .Name }},{{ end }}`,
+			want:     "hub,spoke1,",
+		},
+		{
+			name:     "lookup existing cluster",
+			template: `{{ with lookupCluster "aws" "prod" "spoke3" }}{{ .Environment }}/{{ .Stage }}/{{ .Name }}{{ end }}`,
+			want:     "aws/prod/spoke3",
+		},
+		{
+			name:     "lookup missing cluster",
+			template: `{{ with lookupCluster "aws" "dev" "spoke3" }}{{ .Name }}{{ else }}missing{{ end }}`,
+			want:     "missing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.New("root")
+			tmpl, err := tmpl.Funcs(funcMap(tmpl)).Parse(tt.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := &strings.Builder{}
+			err = tmpl.Funcs(clusterFuncMap(NewClusterSource(func() ([]ClusterData, error) {
+				return clusters, nil
+			}))).Execute(got, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("clusterFuncMap() = %v, want %v", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func Test_clusterFuncMap_lazy(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		loadErr    error
+		want       string
+		wantErr    bool
+		wantLoaded int
+	}{
+		{
+			name:       "broken clusters are not loaded if they are not used",
+			template:   `{{ "static" }}`,
+			loadErr:    fmt.Errorf("broken cluster"),
+			want:       "static",
+			wantLoaded: 0,
+		},
+		{
+			name:       "clusters are loaded once",
+			template:   `{{ len clusters }},{{ with lookupCluster "dev" "dev" "hub" }}{{ .Name }}{{ end }}`,
+			want:       "1,hub",
+			wantLoaded: 1,
+		},
+		{
+			name:       "load errors are returned by the functions",
+			template:   `{{ len clusters }}`,
+			loadErr:    fmt.Errorf("broken cluster"),
+			wantErr:    true,
+			wantLoaded: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded := 0
+			source := NewClusterSource(func() ([]ClusterData, error) {
+				loaded++
+				return []ClusterData{{Environment: "dev", Stage: "dev", Name: "hub"}}, tt.loadErr
+			})
+			tmpl := template.New("root")
+			tmpl, err := tmpl.Funcs(funcMap(tmpl)).Parse(tt.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := &strings.Builder{}
+			err = tmpl.Funcs(clusterFuncMap(source)).Execute(got, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("clusterFuncMap() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("clusterFuncMap() = %v, want %v", got.String(), tt.want)
+			}
+			if loaded != tt.wantLoaded {
+				t.Errorf("clusters loaded %d times, want %d", loaded, tt.wantLoaded)
+			}
+		})
+	}
+}
+
+func Test_sealFuncMap(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		key      *rsa.PublicKey
+		template string
+		wantErr  bool
+	}{
+		{
+			name:     "strict",
+			key:      &key.PublicKey,
+			template: `{{ sealSecret "vault" "unseal" "s3cr3t" }}`,
+		},
+		{
+			name:     "namespace-wide",
+			key:      &key.PublicKey,
+			template: `{{ sealSecretNamespaceWide "vault" "s3cr3t" }}`,
+		},
+		{
+			name:     "cluster-wide",
+			key:      &key.PublicKey,
+			template: `{{ sealSecretClusterWide "s3cr3t" }}`,
+		},
+		{
+			name:     "without certificate",
+			template: `{{ sealSecret "vault" "unseal" "s3cr3t" }}`,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.New("root")
+			tmpl, err := tmpl.Funcs(funcMap(tmpl)).Parse(tt.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := &strings.Builder{}
+			err = tmpl.Funcs(sealFuncMap(tt.key)).Execute(got, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("sealFuncMap() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if _, err := base64.StdEncoding.DecodeString(got.String()); err != nil || got.Len() == 0 {
+				t.Errorf("sealFuncMap() = %v, expected a base64 encoded value", got.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package template
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// GenerateStrategy defines how the value of a property is generated
+type GenerateStrategy string
+
+const (
+	// GenerateStrategyRandom generates a random alphanumeric string
+	GenerateStrategyRandom GenerateStrategy = "random"
+	// GenerateStrategyUUID generates a random UUID
+	GenerateStrategyUUID GenerateStrategy = "uuid"
+	// GenerateStrategySequence generates the next free number of a sequence
+	GenerateStrategySequence GenerateStrategy = "sequence"
+	// GenerateStrategyCIDR allocates the next free network from a pool
+	GenerateStrategyCIDR GenerateStrategy = "cidr"
+
+	defaultRandomLength = 32
+	randomCharset       = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	// maxGenerateAttempts limits the retries of random values that collide with existing values
+	maxGenerateAttempts = 10
+)
+
+// Generator defines how a unique value of a property is generated for each cluster
+type Generator struct {
+	Strategy GenerateStrategy `json:"strategy"`
+	// Length is the number of characters of random values, defaults to 32
+	Length int `json:"length,omitempty"`
+	// Start is the first number of a sequence, defaults to 1
+	Start *int `json:"start,omitempty"`
+	// Pool is the network the cidr strategy allocates networks from
+	Pool string `json:"pool,omitempty"`
+	// PrefixLength is the prefix length of the networks allocated by the cidr strategy
+	PrefixLength int `json:"prefixLength,omitempty"`
+}
+
+// GenerateValue generates a value that is not part of the existing values and validates it against the property definition
+func (p Property) GenerateValue(existing []any) (any, error) {
+	if p.Generate == nil {
+		return nil, fmt.Errorf("property has no generate strategy")
+	}
+	value, err := p.Generate.Generate(existing)
+	if err != nil {
+		return nil, err
+	}
+	property := p
+	property.Default = nil
+	parsed, err := property.ParseValue(value)
+	if err != nil && p.Type == PropertyTypeSecret {
+		// secret values must not be part of error messages
+		return nil, fmt.Errorf("generated value is invalid: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("generated value %q is invalid: %w", value, err)
+	}
+	return parsed, nil
+}
+
+// Generate returns a new value that is not part of the existing values
+// For the cidr strategy, the allocated network does not overlap with any of the existing networks
+func (g Generator) Generate(existing []any) (string, error) {
+	switch g.Strategy {
+	case GenerateStrategyRandom:
+		length := g.Length
+		if length <= 0 {
+			length = defaultRandomLength
+		}
+		return generateUnique(existing, func() (string, error) {
+			return randomString(length)
+		})
+	case GenerateStrategyUUID:
+		return generateUnique(existing, func() (string, error) {
+			return uuid.NewString(), nil
+		})
+	case GenerateStrategySequence:
+		return g.nextSequence(existing), nil
+	case GenerateStrategyCIDR:
+		return g.allocateCIDR(existing)
+	}
+	return "", fmt.Errorf("unknown generate strategy %q", g.Strategy)
+}
+
+// SharesPool checks if both generators allocate networks from overlapping pools, the other generator may be nil
+func (g *Generator) SharesPool(other *Generator) bool {
+	if g == nil || other == nil || g.Strategy != GenerateStrategyCIDR || other.Strategy != GenerateStrategyCIDR {
+		return false
+	}
+	pool, err := netip.ParsePrefix(g.Pool)
+	if err != nil {
+		return g.Pool == other.Pool
+	}
+	otherPool, err := netip.ParsePrefix(other.Pool)
+	if err != nil {
+		return false
+	}
+	return pool.Overlaps(otherPool)
+}
+
+// generateUnique calls the generate function until it returns a value that is not part of the existing values
+func generateUnique(existing []any, generate func() (string, error)) (string, error) {
+	used := map[string]bool{}
+	for _, v := range existing {
+		used[fmt.Sprint(v)] = true
+	}
+	for range maxGenerateAttempts {
+		value, err := generate()
+		if err != nil {
+			return "", err
+		}
+		if !used[value] {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("failed to generate a unique value after %d attempts", maxGenerateAttempts)
+}
+
+// randomString returns a random alphanumeric string of the given length
+func randomString(length int) (string, error) {
+	limit := big.NewInt(int64(len(randomCharset)))
+	result := make([]byte, length)
+	for i := range result {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", err
+		}
+		result[i] = randomCharset[n.Int64()]
+	}
+	return string(result), nil
+}
+
+// nextSequence returns the lowest number of the sequence that is not part of the existing values
+func (g Generator) nextSequence(existing []any) string {
+	used := map[int]bool{}
+	for _, v := range existing {
+		i, err := strconv.Atoi(fmt.Sprint(v))
+		if err != nil {
+			continue
+		}
+		used[i] = true
+	}
+	next := 1
+	if g.Start != nil {
+		next = *g.Start
+	}
+	for used[next] {
+		next++
+	}
+	return strconv.Itoa(next)
+}
+
+// allocateCIDR returns the first network of the pool that does not overlap with the existing networks
+func (g Generator) allocateCIDR(existing []any) (string, error) {
+	pool, err := netip.ParsePrefix(g.Pool)
+	if err != nil {
+		return "", fmt.Errorf("invalid cidr pool %q: %w", g.Pool, err)
+	}
+	pool = pool.Masked()
+	if g.PrefixLength < pool.Bits() || g.PrefixLength > pool.Addr().BitLen() {
+		return "", fmt.Errorf("prefix length %d must be between %d and %d", g.PrefixLength, pool.Bits(), pool.Addr().BitLen())
+	}
+
+	allocated := []netip.Prefix{}
+	for _, v := range existing {
+		prefix, err := netip.ParsePrefix(fmt.Sprint(v))
+		if err != nil {
+			continue
+		}
+		allocated = append(allocated, prefix)
+	}
+
+	// the distance between two networks with the given prefix length
+	step := new(big.Int).Lsh(big.NewInt(1), uint(pool.Addr().BitLen()-g.PrefixLength))
+	addr := pool.Addr()
+	for pool.Contains(addr) {
+		candidate := netip.PrefixFrom(addr, g.PrefixLength)
+		overlaps := false
+		for _, prefix := range allocated {
+			if prefix.Overlaps(candidate) {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			return candidate.String(), nil
+		}
+
+		next, ok := addAddr(addr, step)
+		if !ok {
+			break
+		}
+		addr = next
+	}
+	return "", fmt.Errorf("cidr pool %s is exhausted", g.Pool)
+}
+
+// addAddr adds n to the address, false is returned if the result overflows the address space
+func addAddr(addr netip.Addr, n *big.Int) (netip.Addr, bool) {
+	sum := new(big.Int).Add(new(big.Int).SetBytes(addr.AsSlice()), n)
+	if sum.BitLen() > addr.BitLen() {
+		return netip.Addr{}, false
+	}
+	bts := sum.FillBytes(make([]byte, addr.BitLen()/8))
+	next, ok := netip.AddrFromSlice(bts)
+	return next, ok
+}
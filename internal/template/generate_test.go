@@ -0,0 +1,196 @@
+package template
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/secret"
+)
+
+func TestGenerator_Generate(t *testing.T) {
+	tests := []struct {
+		name      string
+		generator Generator
+		existing  []any
+		want      string
+		wantMatch string
+		wantErr   bool
+	}{
+		{
+			name:      "random with default length",
+			generator: Generator{Strategy: GenerateStrategyRandom},
+			wantMatch: "^[a-zA-Z0-9]{32}$",
+		},
+		{
+			name:      "random with length",
+			generator: Generator{Strategy: GenerateStrategyRandom, Length: 8},
+			wantMatch: "^[a-zA-Z0-9]{8}$",
+		},
+		{
+			name:      "uuid",
+			generator: Generator{Strategy: GenerateStrategyUUID},
+			wantMatch: "^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$",
+		},
+		{
+			name:      "sequence without values",
+			generator: Generator{Strategy: GenerateStrategySequence},
+			want:      "1",
+		},
+		{
+			name:      "sequence fills gaps",
+			generator: Generator{Strategy: GenerateStrategySequence},
+			existing:  []any{1, float64(2), "4"},
+			want:      "3",
+		},
+		{
+			name:      "sequence with start",
+			generator: Generator{Strategy: GenerateStrategySequence, Start: intPtr(100)},
+			existing:  []any{1, 100},
+			want:      "101",
+		},
+		{
+			name:      "first network of the pool",
+			generator: Generator{Strategy: GenerateStrategyCIDR, Pool: "10.128.0.0/14", PrefixLength: 16},
+			want:      "10.128.0.0/16",
+		},
+		{
+			name:      "skips overlapping networks",
+			generator: Generator{Strategy: GenerateStrategyCIDR, Pool: "10.128.0.0/14", PrefixLength: 16},
+			existing:  []any{"10.128.0.0/16", "10.129.128.0/17", "invalid"},
+			want:      "10.130.0.0/16",
+		},
+		{
+			name:      "network covering the pool",
+			generator: Generator{Strategy: GenerateStrategyCIDR, Pool: "10.128.0.0/14", PrefixLength: 16},
+			existing:  []any{"10.0.0.0/8"},
+			wantErr:   true,
+		},
+		{
+			name:      "exhausted pool",
+			generator: Generator{Strategy: GenerateStrategyCIDR, Pool: "10.128.0.0/15", PrefixLength: 16},
+			existing:  []any{"10.128.0.0/16", "10.129.0.0/16"},
+			wantErr:   true,
+		},
+		{
+			name:      "ipv6 pool",
+			generator: Generator{Strategy: GenerateStrategyCIDR, Pool: "fd00::/48", PrefixLength: 64},
+			existing:  []any{"fd00::/64"},
+			want:      "fd00:0:0:1::/64",
+		},
+		{
+			name:      "prefix length outside of the pool",
+			generator: Generator{Strategy: GenerateStrategyCIDR, Pool: "10.128.0.0/14", PrefixLength: 8},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid pool",
+			generator: Generator{Strategy: GenerateStrategyCIDR, Pool: "10.128.0.0", PrefixLength: 16},
+			wantErr:   true,
+		},
+		{
+			name:      "unknown strategy",
+			generator: Generator{Strategy: "password"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.generator.Generate(tt.existing)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Generator.Generate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantMatch != "" {
+				if !regexp.MustCompile(tt.wantMatch).MatchString(got) {
+					t.Errorf("Generator.Generate() = %v, want match %v", got, tt.wantMatch)
+				}
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Generator.Generate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProperty_GenerateValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		property Property
+		existing []any
+		want     any
+		wantErr  bool
+	}{
+		{
+			name:     "sequence as int",
+			property: Property{Type: PropertyTypeInt, Generate: &Generator{Strategy: GenerateStrategySequence}},
+			existing: []any{1},
+			want:     2,
+		},
+		{
+			name:     "cidr with format",
+			property: Property{Type: PropertyTypeString, Format: PropertyFormatCIDR, Generate: &Generator{Strategy: GenerateStrategyCIDR, Pool: "172.30.0.0/16", PrefixLength: 24}},
+			want:     "172.30.0.0/24",
+		},
+		{
+			name:     "generated value violates constraints",
+			property: Property{Type: PropertyTypeString, MaxLength: intPtr(4), Generate: &Generator{Strategy: GenerateStrategyRandom, Length: 8}},
+			wantErr:  true,
+		},
+		{
+			name:     "without strategy",
+			property: Property{Type: PropertyTypeString},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.property.GenerateValue(tt.existing)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Property.GenerateValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Property.GenerateValue() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProperty_GenerateValue_secretError(t *testing.T) {
+	property := Property{Type: PropertyTypeSecret, Pattern: "^-$", Generate: &Generator{Strategy: GenerateStrategyRandom, Length: 8}}
+	_, err := property.GenerateValue(nil)
+	if err == nil {
+		t.Fatal("Property.GenerateValue() error = nil, want error")
+	}
+	want := "generated value is invalid: value " + secret.Mask + " does not match pattern ^-$"
+	if err.Error() != want {
+		t.Errorf("Property.GenerateValue() error = %v, want %v", err, want)
+	}
+}
+
+func TestGenerator_SharesPool(t *testing.T) {
+	cidr := func(pool string) *Generator {
+		return &Generator{Strategy: GenerateStrategyCIDR, Pool: pool, PrefixLength: 24}
+	}
+	tests := []struct {
+		name  string
+		g     *Generator
+		other *Generator
+		want  bool
+	}{
+		{name: "same pool", g: cidr("10.0.0.0/16"), other: cidr("10.0.0.0/16"), want: true},
+		{name: "overlapping pools", g: cidr("10.0.0.0/8"), other: cidr("10.128.0.0/9"), want: true},
+		{name: "separate pools", g: cidr("10.0.0.0/16"), other: cidr("172.16.0.0/16"), want: false},
+		{name: "other strategy", g: cidr("10.0.0.0/16"), other: &Generator{Strategy: GenerateStrategyUUID}, want: false},
+		{name: "no generator", g: cidr("10.0.0.0/16"), other: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.g.SharesPool(tt.other); got != tt.want {
+				t.Errorf("Generator.SharesPool() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package template
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// LoadHelpers walks the given directory and parses all files it finds into a single template set
+// The define blocks of the returned template are available to every template and addon file parsed with it
+// If dir is empty, nil is returned
+func LoadHelpers(dir string) (*template.Template, error) {
+	if dir == "" {
+		return nil, nil
+	}
+
+	helpers := template.New("helpers")
+	helpers.Funcs(funcMap(helpers))
+	err := filepath.WalkDir(dir, func(fpath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			// skip directories
+			return nil
+		}
+
+		bts, err := os.ReadFile(fpath)
+		if err != nil {
+			return err
+		}
+
+		name := strings.TrimPrefix(strings.TrimPrefix(fpath, dir), string(os.PathSeparator))
+		_, err = helpers.New(name).Parse(string(bts))
+		if err != nil {
+			return fmt.Errorf("failed to parse helper file %s: %w", fpath, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return helpers, nil
+}
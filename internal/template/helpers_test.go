@@ -0,0 +1,117 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadHelpers(t *testing.T) {
+	type args struct {
+		helpers  map[string]string
+		template string
+		data     any
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "include define block from helpers",
+			args: args{
+				helpers: map[string]string{
+					"_labels.tpl": `{{- define "labels" }}app.kubernetes.io/managed-by: argocd{{ end -}}`,
+				},
+				template: `labels: {{ include "labels" . }}`,
+				data:     nil,
+			},
+			want:    "labels: app.kubernetes.io/managed-by: argocd",
+			wantErr: false,
+		},
+		{
+			name: "include define block from nested helper file with data",
+			args: args{
+				helpers: map[string]string{
+					"sub/_names.tpl": `{{- define "name" }}{{ .ClusterName }}-app{{ end -}}`,
+				},
+				template: `name: {{ include "name" . }}`,
+				data:     TemplateData{ClusterName: "hugi"},
+			},
+			want:    "name: hugi-app",
+			wantErr: false,
+		},
+		{
+			name: "define block of another helper file is available",
+			args: args{
+				helpers: map[string]string{
+					"_a.tpl": `{{- define "a" }}{{ include "b" . }}{{ end -}}`,
+					"_b.tpl": `{{- define "b" }}b{{ end -}}`,
+				},
+				template: `{{ include "a" . }}`,
+				data:     nil,
+			},
+			want:    "b",
+			wantErr: false,
+		},
+		{
+			name: "invalid helper file",
+			args: args{
+				helpers: map[string]string{
+					"_invalid.tpl": `{{- define "invalid" }}`,
+				},
+				template: ``,
+				data:     nil,
+			},
+			want:    "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.args.helpers {
+				err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0775)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = os.WriteFile(filepath.Join(dir, name), []byte(content), 0664)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			templateFile := filepath.Join(t.TempDir(), "template.yaml")
+			err := os.WriteFile(templateFile, []byte(tt.args.template), 0664)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			helpers, err := LoadHelpers(dir)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadHelpers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			tmpl, err := parseFile(templateFile, helpers)
+			if err != nil {
+				t.Errorf("parseFile() error = %v", err)
+				return
+			}
+
+			got := &strings.Builder{}
+			err = tmpl.Execute(got, tt.args.data)
+			if err != nil {
+				t.Errorf("Execute() error = %v", err)
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("LoadHelpers() = %v, want %v", got.String(), tt.want)
+			}
+		})
+	}
+}
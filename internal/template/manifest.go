@@ -3,12 +3,20 @@ package template
 import (
 	"fmt"
 	"io/fs"
+	"math"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/secret"
 	"sigs.k8s.io/yaml"
 )
 
@@ -27,6 +35,40 @@ type TemplateManifest struct {
 	Annotations map[string]string `json:"annotations"`
 	// Files is a list of relative paths to files that are part of the template
 	Files []string `json:"files"`
+	// Scope defines on which level a base template is rendered, defaults to cluster
+	Scope TemplateScope `json:"scope,omitempty"`
+	// Selector is a map of labels a cluster must have for the base template to be rendered
+	// The selector is only used, if no templates have been selected for the cluster
+	Selector map[string]string `json:"selector,omitempty"`
+}
+
+// MatchesLabels checks if the given labels contain all labels of the selector
+func (t TemplateManifest) MatchesLabels(labels map[string]string) bool {
+	for key, value := range t.Selector {
+		if v, ok := labels[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+type TemplateScope string
+
+const (
+	// TemplateScopeCluster renders the template once per cluster into <basePath>/<env>/<stage>/<cluster>
+	TemplateScopeCluster TemplateScope = "cluster"
+	// TemplateScopeStage renders the template once per stage into <basePath>/<env>/<stage>
+	TemplateScopeStage TemplateScope = "stage"
+	// TemplateScopeEnvironment renders the template once per environment into <basePath>/<env>
+	TemplateScopeEnvironment TemplateScope = "environment"
+)
+
+// GetScope returns the scope of the template, if no scope is defined, the cluster scope is returned
+func (t TemplateManifest) GetScope() TemplateScope {
+	if t.Scope == "" {
+		return TemplateScopeCluster
+	}
+	return t.Scope
 }
 
 type PropertyType string
@@ -35,8 +77,29 @@ const (
 	PropertyTypeString PropertyType = "string"
 	PropertyTypeBool   PropertyType = "bool"
 	PropertyTypeInt    PropertyType = "int"
+	PropertyTypeFloat  PropertyType = "float"
+	// PropertyTypeEnum is a string that must be one of the values defined in the enum of the property
+	PropertyTypeEnum PropertyType = "enum"
+	// PropertyTypeList is a list whose items are validated against the items definition of the property
+	PropertyTypeList PropertyType = "list"
+	// PropertyTypeMap is a map whose values are validated against the nested properties of the property
+	PropertyTypeMap PropertyType = "map"
+	// PropertyTypeObject is an alias for PropertyTypeMap
+	PropertyTypeObject PropertyType = "object"
+	// PropertyTypeSecret is a string that is stored encrypted and masked in all outputs
+	PropertyTypeSecret PropertyType = "secret"
 )
 
+// IsValid checks if the property type is known
+func (p PropertyType) IsValid() bool {
+	switch p {
+	case PropertyTypeString, PropertyTypeBool, PropertyTypeInt, PropertyTypeFloat, PropertyTypeEnum,
+		PropertyTypeList, PropertyTypeMap, PropertyTypeObject, PropertyTypeSecret:
+		return true
+	}
+	return false
+}
+
 // checkType validates the given value against the property type
 // If the value is valid, it will be returned, otherwise an error is returned
 func (p PropertyType) checkType(value any) (any, error) {
@@ -47,7 +110,7 @@ func (p PropertyType) checkType(value any) (any, error) {
 	kind := reflect.TypeOf(value).Kind()
 	typeValue := reflect.ValueOf(value)
 	switch p {
-	case PropertyTypeString:
+	case PropertyTypeString, PropertyTypeEnum, PropertyTypeSecret:
 		v, ok := value.(string)
 		if !ok {
 			return nil, fmt.Errorf("expected type %s, got %v", p, kind)
@@ -66,15 +129,77 @@ func (p PropertyType) checkType(value any) (any, error) {
 		}
 		return nil, fmt.Errorf("expected type %s, got %v", p, kind)
 	case PropertyTypeInt:
-		if kind == reflect.String {
+		switch {
+		case kind == reflect.String:
 			i, err := strconv.Atoi(typeValue.String())
 			if err != nil {
 				return nil, err
 			}
 			return i, nil
+		case typeValue.CanInt():
+			return int(typeValue.Int()), nil
+		case typeValue.CanUint():
+			return int(typeValue.Uint()), nil
+		case typeValue.CanFloat():
+			// numbers unmarshalled from yaml or json are float64
+			f := typeValue.Float()
+			if f != math.Trunc(f) {
+				return nil, fmt.Errorf("expected type %s, got %v", p, f)
+			}
+			return int(f), nil
 		}
-		if kind == reflect.Int {
-			return value, nil
+		return nil, fmt.Errorf("expected type %s, got %v", p, kind)
+	case PropertyTypeFloat:
+		switch {
+		case kind == reflect.String:
+			f, err := strconv.ParseFloat(typeValue.String(), 64)
+			if err != nil {
+				return nil, err
+			}
+			return f, nil
+		case typeValue.CanFloat():
+			return typeValue.Float(), nil
+		case typeValue.CanInt():
+			return float64(typeValue.Int()), nil
+		case typeValue.CanUint():
+			return float64(typeValue.Uint()), nil
+		}
+		return nil, fmt.Errorf("expected type %s, got %v", p, kind)
+	case PropertyTypeList:
+		if kind == reflect.String {
+			// values entered by the user are parsed as yaml, e.g. [a, b]
+			list := []any{}
+			err := yaml.Unmarshal([]byte(typeValue.String()), &list)
+			if err != nil {
+				return nil, fmt.Errorf("expected type %s: %w", p, err)
+			}
+			return list, nil
+		}
+		if kind == reflect.Slice || kind == reflect.Array {
+			list := make([]any, 0, typeValue.Len())
+			for i := 0; i < typeValue.Len(); i++ {
+				list = append(list, typeValue.Index(i).Interface())
+			}
+			return list, nil
+		}
+		return nil, fmt.Errorf("expected type %s, got %v", p, kind)
+	case PropertyTypeMap, PropertyTypeObject:
+		if kind == reflect.String {
+			// values entered by the user are parsed as yaml, e.g. {key: value}
+			m := map[string]any{}
+			err := yaml.Unmarshal([]byte(typeValue.String()), &m)
+			if err != nil {
+				return nil, fmt.Errorf("expected type %s: %w", p, err)
+			}
+			return m, nil
+		}
+		if kind == reflect.Map && typeValue.Type().Key().Kind() == reflect.String {
+			m := make(map[string]any, typeValue.Len())
+			iter := typeValue.MapRange()
+			for iter.Next() {
+				m[iter.Key().String()] = iter.Value().Interface()
+			}
+			return m, nil
 		}
 		return nil, fmt.Errorf("expected type %s, got %v", p, kind)
 	default:
@@ -82,15 +207,83 @@ func (p PropertyType) checkType(value any) (any, error) {
 	}
 }
 
+type PropertyFormat string
+
+const (
+	PropertyFormatURL      PropertyFormat = "url"
+	PropertyFormatCIDR     PropertyFormat = "cidr"
+	PropertyFormatHostname PropertyFormat = "hostname"
+)
+
+var (
+	// hostnameRegex matches a RFC 1123 hostname
+	hostnameRegex = regexp.MustCompile(`^([a-zA-Z0-9]([-a-zA-Z0-9]{0,61}[a-zA-Z0-9])?)(\.[a-zA-Z0-9]([-a-zA-Z0-9]{0,61}[a-zA-Z0-9])?)*$`)
+)
+
+// check validates the given string against the format
+func (f PropertyFormat) check(value string) error {
+	switch f {
+	case PropertyFormatURL:
+		u, err := url.ParseRequestURI(value)
+		if err != nil {
+			return err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%s is not an absolute url", value)
+		}
+		return nil
+	case PropertyFormatCIDR:
+		_, _, err := net.ParseCIDR(value)
+		return err
+	case PropertyFormatHostname:
+		if len(value) > 253 || !hostnameRegex.MatchString(value) {
+			return fmt.Errorf("%s is not a valid hostname", value)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown format %s", f)
+	}
+}
+
 type Property struct {
-	Required    bool
-	Default     any
-	Type        PropertyType
-	Description string
+	Required    bool         `json:"required"`
+	Default     any          `json:"default"`
+	Type        PropertyType `json:"type"`
+	Description string       `json:"description"`
+	// Items defines the property definition of the list items, only used for the list type
+	Items *Property `json:"items,omitempty"`
+	// Properties defines the nested property definitions, only used for the map and object type
+	// If defined, keys that are not part of the properties are rejected
+	Properties map[string]Property `json:"properties,omitempty"`
+	// Enum is a list of allowed values
+	Enum []any `json:"enum,omitempty"`
+	// Pattern is a regular expression string values must match
+	Pattern string `json:"pattern,omitempty"`
+	// MinLength is the minimum length of string values
+	MinLength *int `json:"minLength,omitempty"`
+	// MaxLength is the maximum length of string values
+	MaxLength *int `json:"maxLength,omitempty"`
+	// Format is the format string values must comply with
+	Format PropertyFormat `json:"format,omitempty"`
+	// MergeStrategy defines how list values of different levels are merged, defaults to replace
+	MergeStrategy MergeStrategy `json:"mergeStrategy,omitempty"`
+	// MergeKey is the key of the list items that is used by the mergeByKey strategy
+	MergeKey string `json:"mergeKey,omitempty"`
+	// Generate defines how a unique value is generated for each cluster
+	// The value is generated once and stored in the cluster configuration
+	Generate *Generator `json:"generate,omitempty"`
 }
 
 // Check validates the given value against the property definition
 func (p Property) ParseValue(value any) (any, error) {
+	if value == DeleteMarker {
+		// removes the inherited value while merging
+		return value, nil
+	}
+	if value == nil && p.HasTemplatedDefault() {
+		// templated defaults are validated when they are rendered for a cluster
+		return p.Default, nil
+	}
 	v := p.Default
 	if value != nil {
 		v = value
@@ -101,7 +294,153 @@ func (p Property) ParseValue(value any) (any, error) {
 	if v == nil {
 		return nil, nil
 	}
-	return p.Type.checkType(v)
+	return p.parse(v)
+}
+
+// parse validates the given non nil value against the type, the nested definitions and the constraints of the property
+func (p Property) parse(value any) (any, error) {
+	v, err := p.Type.checkType(value)
+	if err != nil {
+		return nil, err
+	}
+
+	switch p.Type {
+	case PropertyTypeList:
+		if p.Items == nil {
+			break
+		}
+		list := v.([]any)
+		for idx, item := range list {
+			parsed, err := p.Items.ParseValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", idx, err)
+			}
+			list[idx] = parsed
+		}
+	case PropertyTypeMap, PropertyTypeObject:
+		if p.Properties == nil {
+			break
+		}
+		m := v.(map[string]any)
+		for key := range m {
+			if _, ok := p.Properties[key]; !ok {
+				return nil, fmt.Errorf("key %s is not allowed", key)
+			}
+		}
+		for key, property := range p.Properties {
+			parsed, err := property.ParseValue(m[key])
+			if err != nil {
+				return nil, fmt.Errorf("key %s: %w", key, err)
+			}
+			if parsed == nil {
+				continue
+			}
+			m[key] = parsed
+		}
+	}
+
+	err = p.checkConstraints(v)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// checkConstraints validates the given value against the enum and string constraints of the property
+func (p Property) checkConstraints(value any) error {
+	if p.Type == PropertyTypeEnum && len(p.Enum) == 0 {
+		return fmt.Errorf("no allowed values defined for type %s", p.Type)
+	}
+
+	// secret values must not be part of error messages
+	display := value
+	s, isString := value.(string)
+	if isString && p.Type == PropertyTypeSecret {
+		if secret.IsEncrypted(s) {
+			// the constraints have been checked before the value was encrypted
+			return nil
+		}
+		display = secret.Mask
+	}
+	if len(p.Enum) > 0 {
+		found := slices.ContainsFunc(p.Enum, func(allowed any) bool {
+			return fmt.Sprint(allowed) == fmt.Sprint(value)
+		})
+		if !found && p.Type == PropertyTypeSecret {
+			return fmt.Errorf("value %v is not one of the allowed values", display)
+		}
+		if !found {
+			return fmt.Errorf("value %v is not one of %v", display, p.Enum)
+		}
+	}
+
+	if !isString {
+		return nil
+	}
+	if p.MinLength != nil && utf8.RuneCountInString(s) < *p.MinLength {
+		return fmt.Errorf("value must be at least %d characters long", *p.MinLength)
+	}
+	if p.MaxLength != nil && utf8.RuneCountInString(s) > *p.MaxLength {
+		return fmt.Errorf("value must be at most %d characters long", *p.MaxLength)
+	}
+	if p.Pattern != "" {
+		pattern, err := p.pattern()
+		if err != nil {
+			return err
+		}
+		if !pattern.MatchString(s) {
+			return fmt.Errorf("value %s does not match pattern %s", display, p.Pattern)
+		}
+	}
+	if p.Format != "" {
+		err := p.Format.check(s)
+		if err != nil && p.Type == PropertyTypeSecret {
+			return fmt.Errorf("value does not match format %s", p.Format)
+		}
+		if err != nil {
+			return fmt.Errorf("value does not match format %s: %w", p.Format, err)
+		}
+	}
+	return nil
+}
+
+// patterns caches the compiled property patterns, so they are only compiled once
+var patterns sync.Map
+
+// pattern returns the compiled pattern of the property
+func (p Property) pattern() (*regexp.Regexp, error) {
+	if compiled, ok := patterns.Load(p.Pattern); ok {
+		return compiled.(*regexp.Regexp), nil
+	}
+	compiled, err := regexp.Compile(p.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %w", p.Pattern, err)
+	}
+	patterns.Store(p.Pattern, compiled)
+	return compiled, nil
+}
+
+// ValidatePatterns compiles the patterns of the property and its nested properties
+func (p Property) ValidatePatterns() error {
+	if p.Pattern != "" {
+		_, err := p.pattern()
+		if err != nil {
+			return err
+		}
+	}
+	if p.Items != nil {
+		err := p.Items.ValidatePatterns()
+		if err != nil {
+			return fmt.Errorf("items: %w", err)
+		}
+	}
+	for key, property := range p.Properties {
+		err := property.ValidatePatterns()
+		if err != nil {
+			return fmt.Errorf("property %s: %w", key, err)
+		}
+	}
+	return nil
 }
 
 // LoadManifest reads the manifest file at the given path and returns the parsed values
@@ -128,6 +467,12 @@ func LoadManifest(path string) (*TemplateManifest, error) {
 	if err != nil {
 		return nil, err
 	}
+	for key, property := range t.Properties {
+		err := property.ValidatePatterns()
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %w", key, err)
+		}
+	}
 	t.BasePath = t.Name
 	return t, nil
 }
@@ -157,6 +502,12 @@ func LoadTemplateManifest(basePath string) ([]Template, error) {
 			return err
 		}
 
+		switch tm.GetScope() {
+		case TemplateScopeCluster, TemplateScopeStage, TemplateScopeEnvironment:
+		default:
+			return fmt.Errorf("template %s has an unknown scope %s", fpath, tm.Scope)
+		}
+
 		templates = append(templates, Template{
 			Path:             filepath.Dir(fpath),
 			TemplateManifest: *tm,
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/leonsteinhaeuser/openshift-gitops-cli/internal/secret"
 	"sigs.k8s.io/yaml"
 )
 
@@ -77,6 +78,105 @@ func TestPropertyType_checkType(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "int from float64",
+			p:    PropertyTypeInt,
+			args: args{
+				value: float64(42),
+			},
+			want:    int(42),
+			wantErr: false,
+		},
+		{
+			name: "int from float64 with fraction",
+			p:    PropertyTypeInt,
+			args: args{
+				value: 42.5,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "float",
+			p:    PropertyTypeFloat,
+			args: args{
+				value: 42.5,
+			},
+			want:    42.5,
+			wantErr: false,
+		},
+		{
+			name: "float from int",
+			p:    PropertyTypeFloat,
+			args: args{
+				value: 42,
+			},
+			want:    float64(42),
+			wantErr: false,
+		},
+		{
+			name: "float from string",
+			p:    PropertyTypeFloat,
+			args: args{
+				value: "0.5",
+			},
+			want:    0.5,
+			wantErr: false,
+		},
+		{
+			name: "list",
+			p:    PropertyTypeList,
+			args: args{
+				value: []string{"a", "b"},
+			},
+			want:    []any{"a", "b"},
+			wantErr: false,
+		},
+		{
+			name: "list from string",
+			p:    PropertyTypeList,
+			args: args{
+				value: "[a, b]",
+			},
+			want:    []any{"a", "b"},
+			wantErr: false,
+		},
+		{
+			name: "invalid list",
+			p:    PropertyTypeList,
+			args: args{
+				value: true,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "map",
+			p:    PropertyTypeMap,
+			args: args{
+				value: map[string]string{"key": "value"},
+			},
+			want:    map[string]any{"key": "value"},
+			wantErr: false,
+		},
+		{
+			name: "object from string",
+			p:    PropertyTypeObject,
+			args: args{
+				value: "{key: value}",
+			},
+			want:    map[string]any{"key": "value"},
+			wantErr: false,
+		},
+		{
+			name: "invalid map",
+			p:    PropertyTypeMap,
+			args: args{
+				value: []string{"a"},
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "unknown type",
 			p:    "unknown",
@@ -463,6 +563,238 @@ func TestProperty_ParseValue(t *testing.T) {
 	}
 }
 
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestProperty_ParseValue_constraints(t *testing.T) {
+	tests := []struct {
+		name     string
+		property Property
+		value    any
+		want     any
+		wantErr  bool
+	}{
+		{
+			name:     "secret matches pattern",
+			property: Property{Type: PropertyTypeSecret, Pattern: "^[a-z]+$"},
+			value:    "abc",
+			want:     "abc",
+		},
+		{
+			name:     "secret does not match pattern",
+			property: Property{Type: PropertyTypeSecret, Pattern: "^[a-z]+$"},
+			value:    "ABC",
+			wantErr:  true,
+		},
+		{
+			name:     "encrypted secret skips constraints",
+			property: Property{Type: PropertyTypeSecret, Pattern: "^[a-z]+$"},
+			value:    "ENC[secretbox,AAAA]",
+			want:     "ENC[secretbox,AAAA]",
+		},
+		{
+			name:     "enum with allowed value",
+			property: Property{Type: PropertyTypeEnum, Enum: []any{"a", "b"}},
+			value:    "b",
+			want:     "b",
+		},
+		{
+			name:     "enum with not allowed value",
+			property: Property{Type: PropertyTypeEnum, Enum: []any{"a", "b"}},
+			value:    "c",
+			wantErr:  true,
+		},
+		{
+			name:     "enum without allowed values",
+			property: Property{Type: PropertyTypeEnum},
+			value:    "a",
+			wantErr:  true,
+		},
+		{
+			name:     "int with enum",
+			property: Property{Type: PropertyTypeInt, Enum: []any{float64(1), float64(2)}},
+			value:    "2",
+			want:     2,
+		},
+		{
+			name:     "string matches pattern",
+			property: Property{Type: PropertyTypeString, Pattern: "^[a-z]+$"},
+			value:    "abc",
+			want:     "abc",
+		},
+		{
+			name:     "string does not match pattern",
+			property: Property{Type: PropertyTypeString, Pattern: "^[a-z]+$"},
+			value:    "ABC",
+			wantErr:  true,
+		},
+		{
+			name:     "string too short",
+			property: Property{Type: PropertyTypeString, MinLength: intPtr(4)},
+			value:    "abc",
+			wantErr:  true,
+		},
+		{
+			name:     "string too long",
+			property: Property{Type: PropertyTypeString, MaxLength: intPtr(2)},
+			value:    "abc",
+			wantErr:  true,
+		},
+		{
+			name:     "string length counts characters",
+			property: Property{Type: PropertyTypeString, MinLength: intPtr(3), MaxLength: intPtr(3)},
+			value:    "äöü",
+			want:     "äöü",
+		},
+		{
+			name:     "valid url",
+			property: Property{Type: PropertyTypeString, Format: PropertyFormatURL},
+			value:    "https://git.example.com/repo.git",
+			want:     "https://git.example.com/repo.git",
+		},
+		{
+			name:     "invalid url",
+			property: Property{Type: PropertyTypeString, Format: PropertyFormatURL},
+			value:    "git.example.com",
+			wantErr:  true,
+		},
+		{
+			name:     "valid cidr",
+			property: Property{Type: PropertyTypeString, Format: PropertyFormatCIDR},
+			value:    "10.0.0.0/8",
+			want:     "10.0.0.0/8",
+		},
+		{
+			name:     "invalid cidr",
+			property: Property{Type: PropertyTypeString, Format: PropertyFormatCIDR},
+			value:    "10.0.0.0",
+			wantErr:  true,
+		},
+		{
+			name:     "valid hostname",
+			property: Property{Type: PropertyTypeString, Format: PropertyFormatHostname},
+			value:    "monitoring.apps.example.com",
+			want:     "monitoring.apps.example.com",
+		},
+		{
+			name:     "invalid hostname",
+			property: Property{Type: PropertyTypeString, Format: PropertyFormatHostname},
+			value:    "-monitoring_",
+			wantErr:  true,
+		},
+		{
+			name: "list with item type",
+			property: Property{
+				Type:  PropertyTypeList,
+				Items: &Property{Type: PropertyTypeString, Format: PropertyFormatCIDR},
+			},
+			value: []any{"10.0.0.0/8", "192.168.0.0/16"},
+			want:  []any{"10.0.0.0/8", "192.168.0.0/16"},
+		},
+		{
+			name: "list with invalid item",
+			property: Property{
+				Type:  PropertyTypeList,
+				Items: &Property{Type: PropertyTypeString, Format: PropertyFormatCIDR},
+			},
+			value:   []any{"10.0.0.0/8", "invalid"},
+			wantErr: true,
+		},
+		{
+			name: "object with nested properties",
+			property: Property{
+				Type: PropertyTypeObject,
+				Properties: map[string]Property{
+					"key":      {Type: PropertyTypeString, Required: true},
+					"operator": {Type: PropertyTypeEnum, Enum: []any{"Equal", "Exists"}, Default: "Equal"},
+					"seconds":  {Type: PropertyTypeInt},
+				},
+			},
+			value: map[string]any{"key": "node-role", "seconds": float64(300)},
+			want:  map[string]any{"key": "node-role", "operator": "Equal", "seconds": 300},
+		},
+		{
+			name: "object with unknown key",
+			property: Property{
+				Type: PropertyTypeObject,
+				Properties: map[string]Property{
+					"key": {Type: PropertyTypeString},
+				},
+			},
+			value:   map[string]any{"unknown": "value"},
+			wantErr: true,
+		},
+		{
+			name: "object with missing required key",
+			property: Property{
+				Type: PropertyTypeObject,
+				Properties: map[string]Property{
+					"key": {Type: PropertyTypeString, Required: true},
+				},
+			},
+			value:   map[string]any{},
+			wantErr: true,
+		},
+		{
+			name: "list of objects",
+			property: Property{
+				Type: PropertyTypeList,
+				Items: &Property{
+					Type: PropertyTypeMap,
+					Properties: map[string]Property{
+						"effect": {Type: PropertyTypeEnum, Enum: []any{"NoSchedule", "NoExecute"}},
+					},
+				},
+			},
+			value:   "[{effect: NoSchedule}, {effect: Invalid}]",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.property.ParseValue(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Property.ParseValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Property.ParseValue() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProperty_ParseValue_secretErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		property Property
+		want     string
+	}{
+		{
+			name:     "enum",
+			property: Property{Type: PropertyTypeSecret, Enum: []any{"a", "b"}},
+			want:     "value " + secret.Mask + " is not one of the allowed values",
+		},
+		{
+			name:     "pattern",
+			property: Property{Type: PropertyTypeSecret, Pattern: "^[a-z]+$"},
+			want:     "value " + secret.Mask + " does not match pattern ^[a-z]+$",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.property.ParseValue("S3CRET")
+			if err == nil {
+				t.Fatal("Property.ParseValue() error = nil, want error")
+			}
+			if err.Error() != tt.want {
+				t.Errorf("Property.ParseValue() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
 func TestLoadManifest(t *testing.T) {
 	type args struct {
 		path string
@@ -739,6 +1071,57 @@ func mockTemplateFile(path string, templateName string) error {
 	return nil
 }
 
+func TestLoadManifest_invalidPattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+	}{
+		{
+			name:     "property pattern",
+			manifest: "name: test\nproperties:\n  zone:\n    type: string\n    pattern: \"[\"\n",
+		},
+		{
+			name:     "nested property pattern",
+			manifest: "name: test\nproperties:\n  zones:\n    type: list\n    items:\n      type: string\n      pattern: \"[\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			err := os.WriteFile(filepath.Join(dir, "manifest.yaml"), []byte(tt.manifest), 0664)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = LoadManifest(dir)
+			if err == nil || !strings.Contains(err.Error(), "invalid pattern [") {
+				t.Errorf("LoadManifest() error = %v, want invalid pattern", err)
+			}
+		})
+	}
+}
+
+func TestProperty_pattern(t *testing.T) {
+	p := Property{Type: PropertyTypeString, Pattern: "^[a-z]+$"}
+	first, err := p.pattern()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := p.pattern()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Errorf("Property.pattern() compiled the pattern twice")
+	}
+	if !first.MatchString("abc") {
+		t.Errorf("Property.pattern() does not match abc")
+	}
+	_, err = Property{Pattern: "["}.pattern()
+	if err == nil {
+		t.Errorf("Property.pattern() error = nil, want error")
+	}
+}
+
 func TestLoadTemplateManifest(t *testing.T) {
 	type args struct {
 		path string
@@ -843,3 +1226,82 @@ func TestLoadTemplateManifest(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplateManifest_GetScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest TemplateManifest
+		want     TemplateScope
+	}{
+		{
+			name:     "without scope",
+			manifest: TemplateManifest{},
+			want:     TemplateScopeCluster,
+		},
+		{
+			name:     "with stage scope",
+			manifest: TemplateManifest{Scope: TemplateScopeStage},
+			want:     TemplateScopeStage,
+		},
+		{
+			name:     "with environment scope",
+			manifest: TemplateManifest{Scope: TemplateScopeEnvironment},
+			want:     TemplateScopeEnvironment,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.manifest.GetScope(); got != tt.want {
+				t.Errorf("TemplateManifest.GetScope() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateManifest_MatchesLabels(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector map[string]string
+		labels   map[string]string
+		want     bool
+	}{
+		{
+			name:     "without selector",
+			selector: nil,
+			labels:   map[string]string{"role": "hub"},
+			want:     true,
+		},
+		{
+			name:     "matching selector",
+			selector: map[string]string{"role": "hub"},
+			labels:   map[string]string{"role": "hub", "region": "eu"},
+			want:     true,
+		},
+		{
+			name:     "different label value",
+			selector: map[string]string{"role": "hub"},
+			labels:   map[string]string{"role": "spoke"},
+			want:     false,
+		},
+		{
+			name:     "missing label",
+			selector: map[string]string{"role": "hub", "region": "eu"},
+			labels:   map[string]string{"role": "hub"},
+			want:     false,
+		},
+		{
+			name:     "without labels",
+			selector: map[string]string{"role": "hub"},
+			labels:   nil,
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := TemplateManifest{Selector: tt.selector}
+			if got := tm.MatchesLabels(tt.labels); got != tt.want {
+				t.Errorf("TemplateManifest.MatchesLabels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package template
+
+import (
+	"fmt"
+	"slices"
+)
+
+// DeleteMarker can be used as value to remove an inherited key
+const DeleteMarker = "~delete"
+
+// MergeStrategy defines how list values of different levels are merged
+type MergeStrategy string
+
+const (
+	// MergeStrategyReplace replaces the inherited list, this is the default
+	MergeStrategyReplace MergeStrategy = "replace"
+	// MergeStrategyAppend appends the items to the inherited list
+	MergeStrategyAppend MergeStrategy = "append"
+	// MergeStrategyMergeByKey merges the items with the same value of the merge key, other items are appended
+	MergeStrategyMergeByKey MergeStrategy = "mergeByKey"
+)
+
+// MergeProperties deep merges the override into the base properties and returns the result
+// Maps are merged recursively, lists are merged according to the merge strategy of the property definition
+// Keys whose value is the DeleteMarker are removed from the result
+// The given maps are not modified
+func MergeProperties(schema map[string]Property, base, override map[string]any) map[string]any {
+	result := make(map[string]any, len(base)+len(override))
+	for key, value := range base {
+		result[key] = value
+	}
+	for key, value := range override {
+		if value == DeleteMarker {
+			delete(result, key)
+			continue
+		}
+		var property *Property
+		if p, ok := schema[key]; ok {
+			property = &p
+		}
+		inherited, ok := result[key]
+		if !ok {
+			result[key] = removeDeleteMarkers(value)
+			continue
+		}
+		result[key] = property.MergeValue(inherited, value)
+	}
+	return result
+}
+
+// MergeValue deep merges the override into the base value, a nil property merges maps and replaces lists
+func (p *Property) MergeValue(base, override any) any {
+	switch overrideValue := override.(type) {
+	case map[string]any:
+		baseValue, ok := base.(map[string]any)
+		if !ok {
+			return removeDeleteMarkers(override)
+		}
+		var schema map[string]Property
+		if p != nil {
+			schema = p.Properties
+		}
+		return MergeProperties(schema, baseValue, overrideValue)
+	case []any:
+		baseValue, ok := base.([]any)
+		if !ok || p == nil {
+			return removeDeleteMarkers(override)
+		}
+		switch p.MergeStrategy {
+		case MergeStrategyAppend:
+			return append(slices.Clone(baseValue), removeDeleteMarkers(overrideValue).([]any)...)
+		case MergeStrategyMergeByKey:
+			if p.MergeKey == "" {
+				// without a merge key no items can be matched
+				return append(slices.Clone(baseValue), removeDeleteMarkers(overrideValue).([]any)...)
+			}
+			return p.mergeByKey(baseValue, overrideValue)
+		default:
+			return removeDeleteMarkers(override)
+		}
+	default:
+		return override
+	}
+}
+
+// mergeByKey merges the list items that have the same value of the merge key, items without a match are appended
+func (p *Property) mergeByKey(base, override []any) []any {
+	result := slices.Clone(base)
+	for _, item := range override {
+		itemMap, ok := item.(map[string]any)
+		if !ok {
+			result = append(result, removeDeleteMarkers(item))
+			continue
+		}
+		idx := slices.IndexFunc(result, func(inherited any) bool {
+			inheritedMap, ok := inherited.(map[string]any)
+			if !ok {
+				return false
+			}
+			return fmt.Sprint(inheritedMap[p.MergeKey]) == fmt.Sprint(itemMap[p.MergeKey])
+		})
+		if idx == -1 {
+			result = append(result, removeDeleteMarkers(item))
+			continue
+		}
+		result[idx] = p.Items.MergeValue(result[idx], item)
+	}
+	return result
+}
+
+// removeDeleteMarkers removes all keys with the DeleteMarker from the nested maps of the value
+func removeDeleteMarkers(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			if item == DeleteMarker {
+				continue
+			}
+			result[key] = removeDeleteMarkers(item)
+		}
+		return result
+	case []any:
+		result := make([]any, 0, len(v))
+		for _, item := range v {
+			result = append(result, removeDeleteMarkers(item))
+		}
+		return result
+	default:
+		return value
+	}
+}
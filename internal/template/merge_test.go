@@ -0,0 +1,126 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMergeProperties(t *testing.T) {
+	type args struct {
+		schema   map[string]Property
+		base     map[string]any
+		override map[string]any
+	}
+	tests := []struct {
+		name string
+		args args
+		want map[string]any
+	}{
+		{
+			name: "scalar values are replaced",
+			args: args{
+				base:     map[string]any{"replicas": 1, "name": "app"},
+				override: map[string]any{"replicas": 3},
+			},
+			want: map[string]any{"replicas": 3, "name": "app"},
+		},
+		{
+			name: "nested maps are merged",
+			args: args{
+				base: map[string]any{
+					"resources": map[string]any{"limits": map[string]any{"cpu": 1}},
+				},
+				override: map[string]any{
+					"resources": map[string]any{"limits": map[string]any{"memory": "1Gi"}},
+				},
+			},
+			want: map[string]any{
+				"resources": map[string]any{"limits": map[string]any{"cpu": 1, "memory": "1Gi"}},
+			},
+		},
+		{
+			name: "delete marker removes inherited keys",
+			args: args{
+				base: map[string]any{
+					"replicas":  1,
+					"resources": map[string]any{"limits": map[string]any{"cpu": 1, "memory": "1Gi"}},
+				},
+				override: map[string]any{
+					"replicas":  DeleteMarker,
+					"resources": map[string]any{"limits": map[string]any{"cpu": DeleteMarker}},
+				},
+			},
+			want: map[string]any{
+				"resources": map[string]any{"limits": map[string]any{"memory": "1Gi"}},
+			},
+		},
+		{
+			name: "delete marker without inherited key",
+			args: args{
+				base:     map[string]any{},
+				override: map[string]any{"labels": map[string]any{"team": DeleteMarker, "app": "web"}},
+			},
+			want: map[string]any{"labels": map[string]any{"app": "web"}},
+		},
+		{
+			name: "lists are replaced by default",
+			args: args{
+				base:     map[string]any{"hosts": []any{"a", "b"}},
+				override: map[string]any{"hosts": []any{"c"}},
+			},
+			want: map[string]any{"hosts": []any{"c"}},
+		},
+		{
+			name: "lists are appended",
+			args: args{
+				schema:   map[string]Property{"hosts": {Type: PropertyTypeList, MergeStrategy: MergeStrategyAppend}},
+				base:     map[string]any{"hosts": []any{"a", "b"}},
+				override: map[string]any{"hosts": []any{"c"}},
+			},
+			want: map[string]any{"hosts": []any{"a", "b", "c"}},
+		},
+		{
+			name: "lists are merged by key",
+			args: args{
+				schema: map[string]Property{
+					"users": {Type: PropertyTypeList, MergeStrategy: MergeStrategyMergeByKey, MergeKey: "name"},
+				},
+				base: map[string]any{"users": []any{
+					map[string]any{"name": "alice", "role": "admin", "team": "ops"},
+					map[string]any{"name": "bob", "role": "view"},
+				}},
+				override: map[string]any{"users": []any{
+					map[string]any{"name": "alice", "role": "view", "team": DeleteMarker},
+					map[string]any{"name": "carol", "role": "edit"},
+				}},
+			},
+			want: map[string]any{"users": []any{
+				map[string]any{"name": "alice", "role": "view"},
+				map[string]any{"name": "bob", "role": "view"},
+				map[string]any{"name": "carol", "role": "edit"},
+			}},
+		},
+		{
+			name: "nested list strategy",
+			args: args{
+				schema: map[string]Property{
+					"network": {Type: PropertyTypeMap, Properties: map[string]Property{
+						"cidrs": {Type: PropertyTypeList, MergeStrategy: MergeStrategyAppend},
+					}},
+				},
+				base:     map[string]any{"network": map[string]any{"cidrs": []any{"10.0.0.0/8"}}},
+				override: map[string]any{"network": map[string]any{"cidrs": []any{"192.168.0.0/16"}}},
+			},
+			want: map[string]any{"network": map[string]any{"cidrs": []any{"10.0.0.0/8", "192.168.0.0/16"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeProperties(tt.args.schema, tt.args.base, tt.args.override)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("MergeProperties() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const (
+	// overridesDirectory is the directory inside an addon or template that contains environment and stage specific file variants
+	overridesDirectory = "overrides"
+)
+
+// RenderedFile describes a file that has been written during rendering
+type RenderedFile struct {
+	// Path is the location of the rendered file
+	Path string
+	// Source is the location of the file the rendered file was created from
+	Source string
+	// Override indicates that the source is an environment or stage specific variant of the base file
+	Override bool
+}
+
+// resolveOverride returns the location of the file variant that must be used for the given environment and stage
+// The lookup order is <basePath>/overrides/<env>/<stage>/<file>, <basePath>/overrides/<env>/<file> and <basePath>/<file>
+// The returned boolean indicates whether an override was found
+func resolveOverride(basePath, fileName, env, stage string) (string, bool) {
+	candidates := []string{}
+	if env != "" && stage != "" {
+		candidates = append(candidates, filepath.Join(basePath, overridesDirectory, env, stage, fileName))
+	}
+	if env != "" {
+		candidates = append(candidates, filepath.Join(basePath, overridesDirectory, env, fileName))
+	}
+	for _, candidate := range candidates {
+		finfo, err := os.Stat(candidate)
+		if err == nil && !finfo.IsDir() {
+			return candidate, true
+		}
+	}
+	return filepath.Join(basePath, fileName), false
+}
+
+// isOverridesDirectory checks if the given directory is the overrides directory of the addon or template
+func isOverridesDirectory(basePath, dir string) bool {
+	return filepath.Clean(dir) == filepath.Join(basePath, overridesDirectory)
+}
@@ -0,0 +1,160 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_resolveOverride(t *testing.T) {
+	type args struct {
+		files    []string
+		fileName string
+		env      string
+		stage    string
+	}
+	tests := []struct {
+		name         string
+		args         args
+		want         string
+		wantOverride bool
+	}{
+		{
+			name: "without overrides",
+			args: args{
+				files:    []string{"patch.yaml"},
+				fileName: "patch.yaml",
+				env:      "dev",
+				stage:    "dev",
+			},
+			want:         "patch.yaml",
+			wantOverride: false,
+		},
+		{
+			name: "with stage override",
+			args: args{
+				files:    []string{"patch.yaml", "overrides/dev/dev/patch.yaml", "overrides/dev/patch.yaml"},
+				fileName: "patch.yaml",
+				env:      "dev",
+				stage:    "dev",
+			},
+			want:         "overrides/dev/dev/patch.yaml",
+			wantOverride: true,
+		},
+		{
+			name: "with environment override",
+			args: args{
+				files:    []string{"patch.yaml", "overrides/dev/patch.yaml"},
+				fileName: "patch.yaml",
+				env:      "dev",
+				stage:    "dev",
+			},
+			want:         "overrides/dev/patch.yaml",
+			wantOverride: true,
+		},
+		{
+			name: "with override of another stage",
+			args: args{
+				files:    []string{"patch.yaml", "overrides/dev/prod/patch.yaml"},
+				fileName: "patch.yaml",
+				env:      "dev",
+				stage:    "dev",
+			},
+			want:         "patch.yaml",
+			wantOverride: false,
+		},
+		{
+			name: "with nested file",
+			args: args{
+				files:    []string{"config/abc.yaml", "overrides/onprem/config/abc.yaml"},
+				fileName: "config/abc.yaml",
+				env:      "onprem",
+				stage:    "dev",
+			},
+			want:         "overrides/onprem/config/abc.yaml",
+			wantOverride: true,
+		},
+		{
+			name: "without stage",
+			args: args{
+				files:    []string{"patch.yaml", "overrides/dev/patch.yaml"},
+				fileName: "patch.yaml",
+				env:      "dev",
+				stage:    "",
+			},
+			want:         "overrides/dev/patch.yaml",
+			wantOverride: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, file := range tt.args.files {
+				err := os.MkdirAll(filepath.Dir(filepath.Join(dir, file)), 0775)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = os.WriteFile(filepath.Join(dir, file), []byte(file), 0664)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, gotOverride := resolveOverride(dir, tt.args.fileName, tt.args.env, tt.args.stage)
+			if got != filepath.Join(dir, tt.want) {
+				t.Errorf("resolveOverride() got = %v, want %v", got, filepath.Join(dir, tt.want))
+			}
+			if gotOverride != tt.wantOverride {
+				t.Errorf("resolveOverride() gotOverride = %v, want %v", gotOverride, tt.wantOverride)
+			}
+		})
+	}
+}
+
+func TestLoadTemplatesFromAddonManifest_overrides(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"manifest.yaml":                "name: addon",
+		"kustomization.yaml":           "base",
+		"patch.yaml":                   "base",
+		"overrides/onprem/patch.yaml":  "onprem",
+		"overrides/onprem/extra.yaml":  "extra",
+		"overrides/aws/dev/patch.yaml": "aws",
+	}
+	for name, content := range files {
+		err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0775)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(dir, name), []byte(content), 0664)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	atc, err := LoadTemplatesFromAddonManifest(TemplateManifest{
+		Name:     "addon",
+		BasePath: dir,
+		Files:    []string{includeAllInDirectory},
+	}, nil, "onprem", "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"kustomization.yaml": filepath.Join(dir, "kustomization.yaml"),
+		"patch.yaml":         filepath.Join(dir, "overrides/onprem/patch.yaml"),
+	}
+	if diff := cmp.Diff(want, atc.Sources); diff != "" {
+		t.Errorf("LoadTemplatesFromAddonManifest() mismatch (-want +got):\n%s", diff)
+	}
+	wantOverrides := map[string]bool{
+		"kustomization.yaml": false,
+		"patch.yaml":         true,
+	}
+	if diff := cmp.Diff(wantOverrides, atc.Overrides); diff != "" {
+		t.Errorf("LoadTemplatesFromAddonManifest() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,209 @@
+package template
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+const (
+	// ReferenceProperties is the field of the properties of a template or addon
+	ReferenceProperties = "Properties"
+	// ReferenceClusterProperties is the field of the cluster properties in addon files
+	ReferenceClusterProperties = "ClusterProperties"
+	// ReferenceAddons is the field of the addons of a cluster
+	ReferenceAddons = "Addons"
+)
+
+// Reference is an access to a field of the template data
+type Reference struct {
+	// Field is one of Properties, ClusterProperties or Addons
+	Field string
+	// Key is the accessed property or addon, it is empty if the whole field is accessed, e.g. toYaml .Properties
+	Key string
+	// Property is the accessed property of the addon, only used for .Addons.<addon>.Properties.<property>
+	Property string
+	// Line and Column point to the last field of the access
+	Line   int
+	Column int
+}
+
+// referenceFuncs are functions whose first argument is a map and second argument a key of the map
+var referenceFuncs = map[string]bool{
+	"index":  true,
+	"hasKey": true,
+	"get":    true,
+}
+
+// FindReferences parses the template and returns all accesses to the properties and addons of the template data
+// Accesses inside of range and with blocks are only detected if they start at $, because the dot is rebound there
+func FindReferences(name, content string) ([]Reference, error) {
+	tmpl := template.New(name)
+	tmpl, err := tmpl.Funcs(funcMap(tmpl)).Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	refs := &referenceCollector{content: content}
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Tree.Root == nil {
+			continue
+		}
+		refs.walk(t.Tree.Root, true)
+	}
+	return refs.references, nil
+}
+
+// FindFileReferences reads the template file and returns all accesses to the properties and addons of the template data
+func FindFileReferences(path string) ([]Reference, error) {
+	bts, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return FindReferences(path, string(bts))
+}
+
+// SourceFiles returns all files of the manifest in the given directory, including their environment and stage overrides
+// Entries of files may be files or directories, ./ includes all files of the directory
+func SourceFiles(dir string, files []string) ([]string, error) {
+	result := []string{}
+	err := filepath.WalkDir(dir, func(fpath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() == "manifest.yaml" || d.Name() == "manifest.yml" {
+			return nil
+		}
+		fileName := strings.TrimPrefix(strings.TrimPrefix(fpath, filepath.Clean(dir)), string(os.PathSeparator))
+		if strings.HasPrefix(fileName, overridesDirectory+string(os.PathSeparator)) {
+			result = append(result, fpath)
+			return nil
+		}
+		for _, entry := range files {
+			entry = filepath.Clean(entry)
+			if entry == "." || entry == fileName || strings.HasPrefix(fileName, entry+string(os.PathSeparator)) {
+				result = append(result, fpath)
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// referenceCollector walks the parse tree of a template and collects the references
+type referenceCollector struct {
+	content    string
+	references []Reference
+}
+
+// walk collects the references of the node, rootDot indicates that the dot is the template data
+func (r *referenceCollector) walk(node parse.Node, rootDot bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			r.walk(child, rootDot)
+		}
+	case *parse.ActionNode:
+		r.walk(n.Pipe, rootDot)
+	case *parse.IfNode:
+		r.walk(n.Pipe, rootDot)
+		r.walk(n.List, rootDot)
+		r.walk(n.ElseList, rootDot)
+	case *parse.RangeNode:
+		r.walk(n.Pipe, rootDot)
+		r.walk(n.List, false)
+		r.walk(n.ElseList, rootDot)
+	case *parse.WithNode:
+		r.walk(n.Pipe, rootDot)
+		r.walk(n.List, false)
+		r.walk(n.ElseList, rootDot)
+	case *parse.TemplateNode:
+		r.walk(n.Pipe, rootDot)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			r.walk(cmd, rootDot)
+		}
+	case *parse.CommandNode:
+		if len(n.Args) >= 3 {
+			if fn, ok := n.Args[0].(*parse.IdentifierNode); ok && referenceFuncs[fn.Ident] {
+				if key, ok := n.Args[2].(*parse.StringNode); ok {
+					if ident := r.fieldIdent(n.Args[1], rootDot); ident != nil {
+						// index .Properties "key" accesses a single key
+						r.add(n.Args[1], append(ident, key.Text))
+						for _, arg := range n.Args[3:] {
+							r.walk(arg, rootDot)
+						}
+						return
+					}
+				}
+			}
+		}
+		for _, arg := range n.Args {
+			r.walk(arg, rootDot)
+		}
+	case *parse.ChainNode:
+		r.walk(n.Node, rootDot)
+	case *parse.FieldNode, *parse.VariableNode:
+		if ident := r.fieldIdent(n, rootDot); ident != nil {
+			r.add(n, ident)
+		}
+	}
+}
+
+// fieldIdent returns the field chain of the node relative to the template data, nil is returned if it is unknown
+func (r *referenceCollector) fieldIdent(node parse.Node, rootDot bool) []string {
+	switch n := node.(type) {
+	case *parse.FieldNode:
+		if rootDot {
+			return n.Ident
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			return n.Ident[1:]
+		}
+	}
+	return nil
+}
+
+// add adds the reference of the field chain, chains that do not access properties or addons are ignored
+func (r *referenceCollector) add(node parse.Node, ident []string) {
+	if len(ident) == 0 {
+		return
+	}
+	ref := Reference{Field: ident[0]}
+	switch ident[0] {
+	case ReferenceProperties, ReferenceClusterProperties:
+		if len(ident) > 1 {
+			ref.Key = ident[1]
+		}
+	case ReferenceAddons:
+		if len(ident) > 1 {
+			ref.Key = ident[1]
+		}
+		if len(ident) > 3 && ident[2] == ReferenceProperties {
+			ref.Property = ident[3]
+		}
+	default:
+		return
+	}
+	offset := int(node.Position())
+	if offset > len(r.content) {
+		offset = len(r.content)
+	}
+	before := r.content[:offset]
+	ref.Line = strings.Count(before, "\n") + 1
+	ref.Column = offset - strings.LastIndex(before, "\n")
+	r.references = append(r.references, ref)
+}
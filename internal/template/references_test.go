@@ -0,0 +1,129 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFindReferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     []Reference
+		wantErr  bool
+	}{
+		{
+			name:     "property access",
+			template: "host: {{ .Properties.host }}\nport: {{ .ClusterProperties.port | default 80 }}",
+			want: []Reference{
+				{Field: ReferenceProperties, Key: "host", Line: 1, Column: 21},
+				{Field: ReferenceClusterProperties, Key: "port", Line: 2, Column: 28},
+			},
+		},
+		{
+			name:     "whole field",
+			template: "{{ toYaml .Properties }}",
+			want: []Reference{
+				{Field: ReferenceProperties, Line: 1, Column: 11},
+			},
+		},
+		{
+			name:     "index with key",
+			template: `{{ index .Properties "my-key" }}{{ if hasKey $.ClusterProperties "region" }}{{ end }}`,
+			want: []Reference{
+				{Field: ReferenceProperties, Key: "my-key", Line: 1, Column: 10},
+				{Field: ReferenceClusterProperties, Key: "region", Line: 1, Column: 47},
+			},
+		},
+		{
+			name:     "addon properties",
+			template: "{{ if .Addons.monitoring.Enabled }}{{ .Addons.monitoring.Properties.host }}{{ end }}",
+			want: []Reference{
+				{Field: ReferenceAddons, Key: "monitoring", Line: 1, Column: 14},
+				{Field: ReferenceAddons, Key: "monitoring", Property: "host", Line: 1, Column: 46},
+			},
+		},
+		{
+			name:     "rebound dot",
+			template: "{{ range clusters }}{{ .Properties.host }}{{ $.Properties.domain }}{{ end }}{{ with .Properties }}{{ .host }}{{ end }}",
+			want: []Reference{
+				{Field: ReferenceProperties, Key: "domain", Line: 1, Column: 47},
+				{Field: ReferenceProperties, Line: 1, Column: 85},
+			},
+		},
+		{
+			name:     "define blocks",
+			template: `{{ define "host" }}{{ .Properties.host }}{{ end }}{{ .ClusterName }}`,
+			want: []Reference{
+				{Field: ReferenceProperties, Key: "host", Line: 1, Column: 34},
+			},
+		},
+		{
+			name:     "invalid template",
+			template: "{{ .Properties.host ",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindReferences(tt.name, tt.template)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FindReferences() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("FindReferences() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSourceFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"manifest.yaml", "values.yaml", "extra.yaml", "dir/a.yaml", "overrides/dev/values.yaml"} {
+		err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(dir, name), nil, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{
+			name:  "listed files and directories",
+			files: []string{"values.yaml", "dir"},
+			want:  []string{"dir/a.yaml", "overrides/dev/values.yaml", "values.yaml"},
+		},
+		{
+			name:  "all files",
+			files: []string{"./"},
+			want:  []string{"dir/a.yaml", "extra.yaml", "overrides/dev/values.yaml", "values.yaml"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SourceFiles(dir, tt.files)
+			if err != nil {
+				t.Fatalf("SourceFiles() error = %v", err)
+			}
+			for idx := range got {
+				got[idx], _ = filepath.Rel(dir, got[idx])
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("SourceFiles() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	yamlv3 "sigs.k8s.io/yaml/goyaml.v3"
+)
+
+// ValidationError is a problem of a document of a rendered file
+type ValidationError struct {
+	File string
+	// Document is the index of the document in the file, starting at 0
+	Document int
+	Err      error
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s (document %d): %v", e.File, e.Document, e.Err)
+}
+
+func (e ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Validator checks that rendered yaml files are well-formed
+// If a schema directory is given, the Kubernetes resources of the files are validated against the schemas of the directory
+type Validator struct {
+	compiler *jsonschema.Compiler
+	// locations contains the schema urls by group/version/kind
+	locations map[string]string
+	// files contains the schema urls by their path relative to the schema directory
+	files   map[string]string
+	schemas map[string]*jsonschema.Schema
+}
+
+// NewValidator creates a validator for the given schema directory, the directory may be empty
+// The directory may contain OpenAPI documents, whose definitions are identified by x-kubernetes-group-version-kind,
+// and JSON schemas named <kind>-<group>-<version>.json, <kind>-<version>.json or <group>/<kind>_<version>.json
+func NewValidator(schemaDir string) (*Validator, error) {
+	v := &Validator{
+		compiler:  jsonschema.NewCompiler(),
+		locations: map[string]string{},
+		files:     map[string]string{},
+		schemas:   map[string]*jsonschema.Schema{},
+	}
+	if schemaDir == "" {
+		return v, nil
+	}
+	err := filepath.WalkDir(schemaDir, func(fpath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(fpath) != ".json" {
+			return nil
+		}
+		return v.index(schemaDir, fpath)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schemas from %s: %w", schemaDir, err)
+	}
+	return v, nil
+}
+
+// index adds the schemas of the file to the validator
+func (v *Validator) index(schemaDir, fpath string) error {
+	bts, err := os.ReadFile(fpath)
+	if err != nil {
+		return err
+	}
+	doc := map[string]any{}
+	err = json.Unmarshal(bts, &doc)
+	if err != nil {
+		return fmt.Errorf("failed to parse schema %s: %w", fpath, err)
+	}
+	absPath, err := filepath.Abs(fpath)
+	if err != nil {
+		return err
+	}
+	fileURL := (&url.URL{Scheme: "file", Path: filepath.ToSlash(absPath)}).String()
+	err = v.compiler.AddResource(fileURL, bytes.NewReader(bts))
+	if err != nil {
+		return fmt.Errorf("failed to load schema %s: %w", fpath, err)
+	}
+
+	relPath, err := filepath.Rel(schemaDir, fpath)
+	if err != nil {
+		return err
+	}
+	v.files[strings.ToLower(filepath.ToSlash(relPath))] = fileURL
+	for _, gvk := range groupVersionKinds(doc) {
+		v.locations[gvk] = fileURL
+	}
+
+	// OpenAPI v2 and v3 documents
+	definitions := map[string]any{}
+	pointer := ""
+	if defs, ok := doc["definitions"].(map[string]any); ok {
+		definitions, pointer = defs, "#/definitions/"
+	}
+	if components, ok := doc["components"].(map[string]any); ok {
+		if defs, ok := components["schemas"].(map[string]any); ok {
+			definitions, pointer = defs, "#/components/schemas/"
+		}
+	}
+	for name, definition := range definitions {
+		definition, ok := definition.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, gvk := range groupVersionKinds(definition) {
+			v.locations[gvk] = fileURL + pointer + strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+		}
+	}
+	return nil
+}
+
+// groupVersionKinds returns the x-kubernetes-group-version-kind entries of the schema as group/version/kind
+func groupVersionKinds(schema map[string]any) []string {
+	list, _ := schema["x-kubernetes-group-version-kind"].([]any)
+	result := []string{}
+	for _, entry := range list {
+		gvk, ok := entry.(map[string]any)
+		if !ok {
+			continue
+		}
+		group, _ := gvk["group"].(string)
+		version, _ := gvk["version"].(string)
+		kind, _ := gvk["kind"].(string)
+		result = append(result, group+"/"+version+"/"+kind)
+	}
+	return result
+}
+
+// schema returns the schema of the given apiVersion and kind, nil is returned if the directory does not contain one
+func (v *Validator) schema(apiVersion, kind string) (*jsonschema.Schema, error) {
+	group, version, found := strings.Cut(apiVersion, "/")
+	if !found {
+		group, version = "", apiVersion
+	}
+	key := group + "/" + version + "/" + kind
+	if schema, ok := v.schemas[key]; ok {
+		return schema, nil
+	}
+
+	location, ok := v.locations[key]
+	if !ok {
+		names := []string{strings.ToLower(group + "/" + kind + "_" + version + ".json")}
+		if group == "" {
+			names = append(names, strings.ToLower(kind+"-"+version+".json"))
+		} else {
+			names = append(names, strings.ToLower(kind+"-"+strings.Split(group, ".")[0]+"-"+version+".json"))
+		}
+		for _, name := range names {
+			if location, ok = v.files[name]; ok {
+				break
+			}
+		}
+	}
+	if location == "" {
+		v.schemas[key] = nil
+		return nil, nil
+	}
+
+	schema, err := v.compiler.Compile(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile schema of %s %s: %w", apiVersion, kind, err)
+	}
+	v.schemas[key] = schema
+	return schema, nil
+}
+
+// Validate validates all rendered yaml files and returns the problems of all files joined into a single error
+func (v *Validator) Validate(files []RenderedFile) error {
+	errs := []error{}
+	for _, file := range files {
+		for _, err := range v.ValidateFile(file.Path) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateFile parses all documents of the yaml file and validates the Kubernetes resources against their schema
+// Files without .yaml or .yml extension, empty documents and resources without schema are skipped
+func (v *Validator) ValidateFile(fpath string) []ValidationError {
+	if ext := filepath.Ext(fpath); ext != ".yaml" && ext != ".yml" {
+		return nil
+	}
+	bts, err := os.ReadFile(fpath)
+	if err != nil {
+		return []ValidationError{{File: fpath, Err: err}}
+	}
+
+	result := []ValidationError{}
+	decoder := yamlv3.NewDecoder(bytes.NewReader(bts))
+	for document := 0; ; document++ {
+		node := yamlv3.Node{}
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// the decoder can not continue after a syntax error
+			result = append(result, ValidationError{File: fpath, Document: document, Err: err})
+			break
+		}
+		err = v.validateDocument(&node)
+		if err != nil {
+			result = append(result, ValidationError{File: fpath, Document: document, Err: err})
+		}
+	}
+	return result
+}
+
+// validateDocument validates the document against the schema of its apiVersion and kind
+func (v *Validator) validateDocument(node *yamlv3.Node) error {
+	var content any
+	err := node.Decode(&content)
+	if err != nil {
+		return err
+	}
+	resource, ok := content.(map[string]any)
+	if !ok {
+		return nil
+	}
+	apiVersion, _ := resource["apiVersion"].(string)
+	kind, _ := resource["kind"].(string)
+	if apiVersion == "" || kind == "" {
+		// not a Kubernetes resource
+		return nil
+	}
+	schema, err := v.schema(apiVersion, kind)
+	if err != nil || schema == nil {
+		return err
+	}
+
+	// the schema validator expects the types of encoding/json
+	bts, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(bts))
+	decoder.UseNumber()
+	var instance any
+	err = decoder.Decode(&instance)
+	if err != nil {
+		return err
+	}
+
+	err = schema.Validate(instance)
+	var verr *jsonschema.ValidationError
+	if errors.As(err, &verr) {
+		messages := validationMessages(verr)
+		sort.Strings(messages)
+		return fmt.Errorf("%s %s is invalid: %s", apiVersion, kind, strings.Join(messages, "; "))
+	}
+	return err
+}
+
+// validationMessages returns the messages of the leaf causes of the validation error
+func validationMessages(err *jsonschema.ValidationError) []string {
+	if len(err.Causes) == 0 {
+		location := err.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		return []string{location + ": " + err.Message}
+	}
+	messages := []string{}
+	for _, cause := range err.Causes {
+		messages = append(messages, validationMessages(cause)...)
+	}
+	return messages
+}
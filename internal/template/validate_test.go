@@ -0,0 +1,129 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testOpenAPISchema = `{
+  "definitions": {
+    "io.k8s.api.core.v1.ConfigMap": {
+      "type": "object",
+      "properties": {
+        "apiVersion": {"type": "string"},
+        "kind": {"type": "string"},
+        "metadata": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"},
+        "data": {"type": "object", "additionalProperties": {"type": "string"}}
+      },
+      "x-kubernetes-group-version-kind": [{"group": "", "kind": "ConfigMap", "version": "v1"}]
+    },
+    "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"}
+      }
+    }
+  }
+}`
+
+const testDeploymentSchema = `{
+  "type": "object",
+  "required": ["spec"],
+  "properties": {
+    "spec": {
+      "type": "object",
+      "properties": {
+        "replicas": {"type": "integer"}
+      }
+    }
+  }
+}`
+
+func TestValidator_ValidateFile(t *testing.T) {
+	schemaDir := t.TempDir()
+	for name, content := range map[string]string{
+		"openapi.json":            testOpenAPISchema,
+		"deployment-apps-v1.json": testDeploymentSchema,
+	} {
+		err := os.WriteFile(filepath.Join(schemaDir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		schemaDir string
+		fileName  string
+		content   string
+		want      []string
+	}{
+		{
+			name:     "no yaml file",
+			fileName: "README.md",
+			content:  "key: [",
+			want:     []string{},
+		},
+		{
+			name:     "multiple documents",
+			fileName: "resources.yaml",
+			content:  "---\n# comment only\n---\napiVersion: v1\nkind: ConfigMap\n---\nkey: value\n",
+			want:     []string{},
+		},
+		{
+			name:     "broken document",
+			fileName: "resources.yml",
+			content:  "apiVersion: v1\nkind: ConfigMap\n---\ndata:\n  key: [value\n",
+			want:     []string{"resources.yml (document 1): yaml: line 4: did not find expected ',' or ']'"},
+		},
+		{
+			name:      "openapi schema",
+			schemaDir: schemaDir,
+			fileName:  "configmap.yaml",
+			content:   "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: 1\ndata:\n  replicas: 3\n  name: test\n",
+			want:      []string{"configmap.yaml (document 0): v1 ConfigMap is invalid: /data/replicas: expected string, but got number; /metadata/name: expected string, but got number"},
+		},
+		{
+			name:      "json schema",
+			schemaDir: schemaDir,
+			fileName:  "deployment.yaml",
+			content:   "apiVersion: apps/v1\nkind: Deployment\nspec:\n  replicas: 2\n---\napiVersion: apps/v1\nkind: Deployment\nspec:\n  replicas: two\n---\napiVersion: apps/v1\nkind: Deployment\n",
+			want: []string{
+				"deployment.yaml (document 1): apps/v1 Deployment is invalid: /spec/replicas: expected integer, but got string",
+				"deployment.yaml (document 2): apps/v1 Deployment is invalid: /: missing properties: 'spec'",
+			},
+		},
+		{
+			name:      "resource without schema",
+			schemaDir: schemaDir,
+			fileName:  "kustomization.yaml",
+			content:   "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources: 3\n",
+			want:      []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			fpath := filepath.Join(dir, tt.fileName)
+			err := os.WriteFile(fpath, []byte(tt.content), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v, err := NewValidator(tt.schemaDir)
+			if err != nil {
+				t.Fatalf("NewValidator() error = %v", err)
+			}
+			got := []string{}
+			for _, verr := range v.ValidateFile(fpath) {
+				verr.File = filepath.Base(verr.File)
+				got = append(got, verr.Error())
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ValidateFile() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}